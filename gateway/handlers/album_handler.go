@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"github.com/SZabrodskii/music-library/utils/providers"
+	"github.com/SZabrodskii/music-library/utils/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type AlbumHandler struct {
+	cache  *providers.CacheProvider
+	client *services.SongServiceClient
+	logger *zap.Logger
+}
+
+func NewAlbumHandler(cache *providers.CacheProvider, client *services.SongServiceClient, logger *zap.Logger) *AlbumHandler {
+	return &AlbumHandler{
+		cache:  cache,
+		client: client,
+		logger: logger,
+	}
+}
+
+// GetAlbums godoc
+// @Summary Get albums
+// @Description Get albums ordered by title, without their tracks
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Limit per page" default(10)
+// @Success 200 {object} services.GetAlbumsResponse
+// @Router /api/v1/albums [get]
+func (h *AlbumHandler) GetAlbums(c *gin.Context) {
+	page := c.DefaultQuery("page", "1")
+	pageSize := c.DefaultQuery("pageSize", "10")
+
+	h.logger.Debug("Got req to get albums",
+		zap.String("page", page),
+		zap.String("pageSize", pageSize),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	response, err := h.client.GetAlbums(&services.GetAlbumsRequest{
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		respondError(c, h.logger, "Failed to get albums", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetAlbum godoc
+// @Summary Get an album
+// @Description Get an album by ID with its tracks ordered by disc and track number
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param albumId path int true "Album ID"
+// @Success 200 {object} models.Album
+// @Failure 404 {object} services.ResponseError
+// @Router /api/v1/albums/{albumId} [get]
+func (h *AlbumHandler) GetAlbum(c *gin.Context) {
+	albumId := c.Param("albumId")
+
+	h.logger.Debug("Got req to get album",
+		zap.String("albumId", albumId),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	album, err := h.client.GetAlbum(&services.GetAlbumRequest{AlbumId: albumId})
+	if err != nil {
+		respondError(c, h.logger, "Failed to get album", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, album)
+}
+
+// CreateAlbum godoc
+// @Summary Create an album
+// @Description Create an album with its track listing
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param album body models.Album true "Album data"
+// @Param Idempotency-Key header string false "Key that makes retries of this request return the first response"
+// @Success 201 {object} models.Album
+// @Header 201 {string} Location "/api/v1/albums/{albumId}"
+// @Failure 400 {object} services.ResponseError
+// @Failure 409 {object} services.ResponseError
+// @Failure 422 {object} services.ResponseError
+// @Router /api/v1/albums [post]
+func (h *AlbumHandler) CreateAlbum(c *gin.Context) {
+	var album models.Album
+	if err := c.ShouldBindJSON(&album); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.logger.Debug("Got req to create album",
+		zap.Any("album", album),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	created, err := h.client.CreateAlbum(&services.CreateAlbumRequest{Album: album})
+	if err != nil {
+		respondError(c, h.logger, "Failed to create album", err)
+		return
+	}
+
+	h.invalidate()
+	c.Header("Location", fmt.Sprintf("/api/v1/albums/%d", created.ID))
+	c.JSON(http.StatusCreated, created)
+}
+
+// UpdateAlbum godoc
+// @Summary Update an album
+// @Description Update an album by ID; tracks, if given, replace the whole track listing
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param albumId path int true "Album ID"
+// @Param album body models.Album true "Album data"
+// @Param Idempotency-Key header string false "Key that makes retries of this request return the first response"
+// @Success 200 {object} models.Album
+// @Failure 400 {object} services.ResponseError
+// @Failure 404 {object} services.ResponseError
+// @Failure 409 {object} services.ResponseError
+// @Failure 422 {object} services.ResponseError
+// @Router /api/v1/albums/{albumId} [patch]
+func (h *AlbumHandler) UpdateAlbum(c *gin.Context) {
+	albumId := c.Param("albumId")
+	var album models.Album
+	if err := c.ShouldBindJSON(&album); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.logger.Debug("Got req to update album",
+		zap.String("albumId", albumId),
+		zap.Any("album", album),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	updated, err := h.client.UpdateAlbum(&services.UpdateAlbumRequest{
+		AlbumId: albumId,
+		Album:   album,
+	})
+	if err != nil {
+		respondError(c, h.logger, "Failed to update album", err)
+		return
+	}
+
+	h.invalidate()
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteAlbum godoc
+// @Summary Delete an album
+// @Description Delete an album by ID; its songs are kept
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param albumId path int true "Album ID"
+// @Param Idempotency-Key header string false "Key that makes retries of this request return the first response"
+// @Success 204
+// @Failure 404 {object} services.ResponseError
+// @Failure 409 {object} services.ResponseError
+// @Failure 422 {object} services.ResponseError
+// @Router /api/v1/albums/{albumId} [delete]
+func (h *AlbumHandler) DeleteAlbum(c *gin.Context) {
+	albumId := c.Param("albumId")
+
+	h.logger.Debug("Got req to delete album",
+		zap.String("albumId", albumId),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	if err := h.client.DeleteAlbum(&services.DeleteAlbumRequest{AlbumId: albumId}); err != nil {
+		respondError(c, h.logger, "Failed to delete album", err)
+		return
+	}
+
+	h.invalidate()
+	c.Status(http.StatusNoContent)
+}
+
+// invalidate drops cached albums and songs, which list the albums they appear on.
+func (h *AlbumHandler) invalidate() {
+	h.cache.DeleteByPrefix("albums:")
+	h.cache.DeleteByPrefix("song:")
+}
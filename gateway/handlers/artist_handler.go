@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/SZabrodskii/music-library/utils/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type ArtistHandler struct {
+	client *services.SongServiceClient
+	logger *zap.Logger
+}
+
+func NewArtistHandler(client *services.SongServiceClient, logger *zap.Logger) *ArtistHandler {
+	return &ArtistHandler{
+		client: client,
+		logger: logger,
+	}
+}
+
+// GetArtists godoc
+// @Summary Get artists
+// @Description Get artists ordered by name, with the number of songs they are credited on
+// @Tags artists
+// @Accept json
+// @Produce json
+// @Param q query string false "Part of the artist name"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Limit per page" default(10)
+// @Success 200 {object} services.GetArtistsResponse
+// @Router /api/v1/artists [get]
+func (h *ArtistHandler) GetArtists(c *gin.Context) {
+	query := c.Query("q")
+	page := c.DefaultQuery("page", "1")
+	pageSize := c.DefaultQuery("pageSize", "10")
+
+	h.logger.Debug("Got req to get artists",
+		zap.String("q", query),
+		zap.String("page", page),
+		zap.String("pageSize", pageSize),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	response, err := h.client.GetArtists(&services.GetArtistsRequest{
+		Query:    query,
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		h.logger.Error("Failed to get artists", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetArtist godoc
+// @Summary Get an artist
+// @Description Get an artist by ID with the songs they are credited on and their role
+// @Tags artists
+// @Accept json
+// @Produce json
+// @Param artistId path int true "Artist ID"
+// @Success 200 {object} models.ArtistWithSongs
+// @Failure 404 {object} services.ResponseError
+// @Router /api/v1/artists/{artistId} [get]
+func (h *ArtistHandler) GetArtist(c *gin.Context) {
+	artistId := c.Param("artistId")
+
+	h.logger.Debug("Got req to get artist",
+		zap.String("artistId", artistId),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	artist, err := h.client.GetArtist(&services.GetArtistRequest{ArtistId: artistId})
+	if err != nil {
+		var respErr *services.ResponseError
+		if errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound {
+			c.JSON(http.StatusNotFound, respErr)
+			return
+		}
+		h.logger.Error("Failed to get artist", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, artist)
+}
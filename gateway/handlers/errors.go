@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/SZabrodskii/music-library/utils/filters"
+	"github.com/SZabrodskii/music-library/utils/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// respondError answers 400 for invalid filters or sort keys and passes client errors of the song service
+// through. Anything else is logged with message and answered with 500.
+func respondError(c *gin.Context, logger *zap.Logger, message string, err error) {
+	var respErr *services.ResponseError
+	switch {
+	case errors.Is(err, filters.ErrInvalidFilter), errors.Is(err, filters.ErrInvalidSort):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.As(err, &respErr) && respErr.StatusCode < http.StatusInternalServerError:
+		c.JSON(respErr.StatusCode, respErr)
+	default:
+		logger.Error(message, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
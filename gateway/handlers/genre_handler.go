@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"github.com/SZabrodskii/music-library/utils/models"
+	"github.com/SZabrodskii/music-library/utils/providers"
+	"github.com/SZabrodskii/music-library/utils/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type GenreHandler struct {
+	cache  *providers.CacheProvider
+	client *services.SongServiceClient
+	logger *zap.Logger
+}
+
+func NewGenreHandler(cache *providers.CacheProvider, client *services.SongServiceClient, logger *zap.Logger) *GenreHandler {
+	return &GenreHandler{
+		cache:  cache,
+		client: client,
+		logger: logger,
+	}
+}
+
+// GetGenres godoc
+// @Summary Get genres
+// @Description Get the genre tree, each genre with the number of songs in it or its descendants
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Success 200 {object} services.GetGenresResponse
+// @Router /api/v1/genres [get]
+func (h *GenreHandler) GetGenres(c *gin.Context) {
+	h.logger.Debug("Got req to get genres",
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	response, err := h.client.GetGenres()
+	if err != nil {
+		respondError(c, h.logger, "Failed to get genres", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateGenre godoc
+// @Summary Create a genre
+// @Description Create a genre, optionally below a parent genre
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param genre body models.Genre true "Genre data"
+// @Param Idempotency-Key header string false "Key that makes retries of this request return the first response"
+// @Success 201 {object} models.Genre
+// @Failure 400 {object} services.ResponseError
+// @Failure 409 {object} services.ResponseError
+// @Failure 422 {object} services.ResponseError
+// @Router /api/v1/genres [post]
+func (h *GenreHandler) CreateGenre(c *gin.Context) {
+	var genre models.Genre
+	if err := c.ShouldBindJSON(&genre); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.logger.Debug("Got req to create genre",
+		zap.Any("genre", genre),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	created, err := h.client.CreateGenre(&services.CreateGenreRequest{Genre: genre})
+	if err != nil {
+		respondError(c, h.logger, "Failed to create genre", err)
+		return
+	}
+
+	h.cache.DeleteByPrefix("genres:")
+	c.JSON(http.StatusCreated, created)
+}
+
+// AttachGenre godoc
+// @Summary Attach a genre to a song
+// @Description Put a song in a genre; attaching it twice is a no-op
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param songId path int true "Song ID"
+// @Param genre path string true "Genre slug or name"
+// @Param Idempotency-Key header string false "Key that makes retries of this request return the first response"
+// @Success 204
+// @Failure 404 {object} services.ResponseError
+// @Failure 409 {object} services.ResponseError
+// @Failure 422 {object} services.ResponseError
+// @Router /api/v1/songs/{songId}/genres/{genre} [put]
+func (h *GenreHandler) AttachGenre(c *gin.Context) {
+	request := &services.SongGenreRequest{SongId: c.Param("songId"), Genre: c.Param("genre")}
+
+	h.logger.Debug("Got req to attach genre",
+		zap.String("songId", request.SongId),
+		zap.String("genre", request.Genre),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	if err := h.client.AttachGenre(request); err != nil {
+		respondError(c, h.logger, "Failed to attach genre", err)
+		return
+	}
+
+	h.invalidate(request.SongId)
+	c.Status(http.StatusNoContent)
+}
+
+// DetachGenre godoc
+// @Summary Detach a genre from a song
+// @Description Remove a song from a genre it was attached to
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param songId path int true "Song ID"
+// @Param genre path string true "Genre slug or name"
+// @Param Idempotency-Key header string false "Key that makes retries of this request return the first response"
+// @Success 204
+// @Failure 404 {object} services.ResponseError
+// @Failure 409 {object} services.ResponseError
+// @Failure 422 {object} services.ResponseError
+// @Router /api/v1/songs/{songId}/genres/{genre} [delete]
+func (h *GenreHandler) DetachGenre(c *gin.Context) {
+	request := &services.SongGenreRequest{SongId: c.Param("songId"), Genre: c.Param("genre")}
+
+	h.logger.Debug("Got req to detach genre",
+		zap.String("songId", request.SongId),
+		zap.String("genre", request.Genre),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	if err := h.client.DetachGenre(request); err != nil {
+		respondError(c, h.logger, "Failed to detach genre", err)
+		return
+	}
+
+	h.invalidate(request.SongId)
+	c.Status(http.StatusNoContent)
+}
+
+func (h *GenreHandler) invalidate(songId string) {
+	h.cache.DeleteFromCache("song:" + songId)
+	h.cache.DeleteByPrefix("songs:")
+	h.cache.DeleteByPrefix("genres:")
+	h.cache.DeleteByPrefix("stats:")
+}
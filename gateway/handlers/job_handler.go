@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/SZabrodskii/music-library/utils/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type JobHandler struct {
+	client *services.SongServiceClient
+	logger *zap.Logger
+}
+
+func NewJobHandler(client *services.SongServiceClient, logger *zap.Logger) *JobHandler {
+	return &JobHandler{
+		client: client,
+		logger: logger,
+	}
+}
+
+// GetJob godoc
+// @Summary Get job status
+// @Description Get the status of an asynchronous add, update, delete, refresh or revert song job
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Param jobId path string true "Job ID"
+// @Success 200 {object} models.Job
+// @Failure 404 {object} services.ResponseError
+// @Router /api/v1/jobs/{jobId} [get]
+func (h *JobHandler) GetJob(c *gin.Context) {
+	jobId := c.Param("jobId")
+
+	h.logger.Debug("Got req to get job",
+		zap.String("jobId", jobId),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	job, err := h.client.GetJob(&services.GetJobRequest{JobId: jobId})
+	if err != nil {
+		var respErr *services.ResponseError
+		if errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound {
+			c.JSON(http.StatusNotFound, respErr)
+			return
+		}
+		h.logger.Error("Failed to get job", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
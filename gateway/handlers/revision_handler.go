@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"github.com/SZabrodskii/music-library/utils/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type RevisionHandler struct {
+	client *services.SongServiceClient
+	logger *zap.Logger
+}
+
+func NewRevisionHandler(client *services.SongServiceClient, logger *zap.Logger) *RevisionHandler {
+	return &RevisionHandler{
+		client: client,
+		logger: logger,
+	}
+}
+
+// GetSongHistory godoc
+// @Summary Get song history
+// @Description Get the revisions of a song, newest first, each with a full snapshot, its diff from the previous revision, the actor and the trace ID
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param songId path int true "Song ID"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Limit per page" default(10)
+// @Success 200 {object} services.GetSongHistoryResponse
+// @Failure 404 {object} services.ResponseError
+// @Router /api/v1/songs/{songId}/history [get]
+func (h *RevisionHandler) GetSongHistory(c *gin.Context) {
+	request := &services.GetSongHistoryRequest{
+		SongId:   c.Param("songId"),
+		Page:     c.DefaultQuery("page", "1"),
+		PageSize: c.DefaultQuery("pageSize", "10"),
+	}
+
+	h.logger.Debug("Got req to get song history",
+		zap.String("songId", request.SongId),
+		zap.String("page", request.Page),
+		zap.String("pageSize", request.PageSize),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	response, err := h.client.GetSongHistory(request)
+	if err != nil {
+		respondError(c, h.logger, "Failed to get song history", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RevertSong godoc
+// @Summary Revert a song
+// @Description Restore the fields, verses and deletion of a song to a revision from its history; the result is recorded as a new revision
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param songId path int true "Song ID"
+// @Param revision path int true "Revision number"
+// @Param X-Actor header string false "Who makes the change, recorded in the song history"
+// @Param Idempotency-Key header string false "Key that makes retries of this request return the first response"
+// @Success 202 {object} models.Job
+// @Header 202 {string} Location "/api/v1/jobs/{jobId}"
+// @Failure 404 {object} services.ResponseError
+// @Failure 409 {object} services.ResponseError
+// @Failure 422 {object} services.ResponseError
+// @Router /api/v1/songs/{songId}/revert/{revision} [post]
+func (h *RevisionHandler) RevertSong(c *gin.Context) {
+	request := &services.RevertSongRequest{
+		SongId:   c.Param("songId"),
+		Revision: c.Param("revision"),
+		Caller:   caller(c),
+	}
+
+	h.logger.Debug("Got req to revert song",
+		zap.String("songId", request.SongId),
+		zap.String("revision", request.Revision),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	job, err := h.client.RevertSong(request)
+	if err != nil {
+		respondError(c, h.logger, "Failed to revert song", err)
+		return
+	}
+
+	c.Header("Location", "/api/v1/jobs/"+job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
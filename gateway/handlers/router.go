@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"github.com/SZabrodskii/music-library/utils"
 	"github.com/SZabrodskii/music-library/utils/middleware"
 	"github.com/SZabrodskii/music-library/utils/providers"
 	"github.com/gin-gonic/gin"
@@ -12,7 +13,7 @@ type Router struct {
 	engine *gin.Engine
 }
 
-func NewRouter(logger *zap.Logger, cache *providers.CacheProvider, songHandler *SongHandler) *Router {
+func NewRouter(logger *zap.Logger, cache *providers.CacheProvider, songHandler *SongHandler, jobHandler *JobHandler, artistHandler *ArtistHandler, albumHandler *AlbumHandler, genreHandler *GenreHandler, tagHandler *TagHandler, statsHandler *StatsHandler, revisionHandler *RevisionHandler) *Router {
 	router := gin.New()
 	router.Use(middleware.TraceParentMiddleware())
 	router.Use(gin.Recovery())
@@ -29,12 +30,35 @@ func NewRouter(logger *zap.Logger, cache *providers.CacheProvider, songHandler *
 		)
 	})
 	router.Use(middleware.CacheMiddleware(cache))
+	router.Use(middleware.IdempotencyMiddleware(cache, time.Duration(utils.GetEnv("IDEMPOTENCY_TTL_HOURS", 24))*time.Hour))
 
 	router.GET("/api/v1/songs", songHandler.GetSongs)
+	router.GET("/api/v1/songs/:songId", songHandler.GetSong)
 	router.GET("/api/v1/songs/:songId/text", songHandler.GetSongText)
+	router.GET("/api/v1/search", songHandler.SearchLyrics)
+	router.GET("/api/v1/search/songs", songHandler.SearchSongs)
 	router.DELETE("/api/v1/songs/:songId", songHandler.DeleteSong)
 	router.PATCH("/api/v1/songs/:songId", songHandler.UpdateSong)
 	router.POST("/api/v1/songs", songHandler.AddSong)
+	router.POST("/api/v1/songs/:songId/refresh", songHandler.RefreshSong)
+	router.GET("/api/v1/songs/:songId/history", revisionHandler.GetSongHistory)
+	router.POST("/api/v1/songs/:songId/revert/:revision", revisionHandler.RevertSong)
+	router.GET("/api/v1/jobs/:jobId", jobHandler.GetJob)
+	router.GET("/api/v1/artists", artistHandler.GetArtists)
+	router.GET("/api/v1/artists/:artistId", artistHandler.GetArtist)
+	router.GET("/api/v1/albums", albumHandler.GetAlbums)
+	router.GET("/api/v1/albums/:albumId", albumHandler.GetAlbum)
+	router.POST("/api/v1/albums", albumHandler.CreateAlbum)
+	router.PATCH("/api/v1/albums/:albumId", albumHandler.UpdateAlbum)
+	router.DELETE("/api/v1/albums/:albumId", albumHandler.DeleteAlbum)
+	router.GET("/api/v1/genres", genreHandler.GetGenres)
+	router.POST("/api/v1/genres", genreHandler.CreateGenre)
+	router.PUT("/api/v1/songs/:songId/genres/:genre", genreHandler.AttachGenre)
+	router.DELETE("/api/v1/songs/:songId/genres/:genre", genreHandler.DetachGenre)
+	router.GET("/api/v1/tags", tagHandler.GetTags)
+	router.PUT("/api/v1/songs/:songId/tags/:tag", tagHandler.AttachTag)
+	router.DELETE("/api/v1/songs/:songId/tags/:tag", tagHandler.DetachTag)
+	router.GET("/api/v1/stats/facets", statsHandler.GetFacetStats)
 
 	return &Router{engine: router}
 
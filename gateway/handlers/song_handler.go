@@ -1,8 +1,8 @@
 package handlers
 
 import (
+	"github.com/SZabrodskii/music-library/utils/middleware"
 	"github.com/SZabrodskii/music-library/utils/models"
-	"github.com/SZabrodskii/music-library/utils/providers"
 	"github.com/SZabrodskii/music-library/utils/services"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -10,14 +10,12 @@ import (
 )
 
 type SongHandler struct {
-	cache  *providers.CacheProvider
 	client *services.SongServiceClient
 	logger *zap.Logger
 }
 
-func NewSongHandler(cache *providers.CacheProvider, client *services.SongServiceClient, logger *zap.Logger) *SongHandler {
+func NewSongHandler(client *services.SongServiceClient, logger *zap.Logger) *SongHandler {
 	return &SongHandler{
-		cache:  cache,
 		client: client,
 		logger: logger,
 	}
@@ -31,31 +29,51 @@ func NewSongHandler(cache *providers.CacheProvider, client *services.SongService
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param pageSize query int false "Limit per page" default(10)
-// @Param filters query []string false "Filters"
-// @Success 200 {array} models.Song
+// @Param cursor query string false "Opaque cursor from nextCursor; switches to keyset pagination"
+// @Param limit query int false "Limit per page in keyset pagination" default(10)
+// @Param filters query []string false "Filters in the form field:operator:value, e.g. group:eq:Muse or genre:in:rock,jazz" collectionFormat(multi)
+// @Param sort query string false "Comma-separated sort keys (id, group, song, releaseDate, createdAt), prefix with - for descending, e.g. group,-releaseDate"
+// @Param releasedAfter query string false "Only songs released on or after this date (YYYY-MM-DD or DD.MM.YYYY)"
+// @Param releasedBefore query string false "Only songs released on or before this date (YYYY-MM-DD or DD.MM.YYYY)"
+// @Success 200 {object} services.GetSongsResponse
+// @Failure 400 {object} services.ResponseError
 // @Router /api/v1/songs [get]
 func (h *SongHandler) GetSongs(c *gin.Context) {
 	page := c.DefaultQuery("page", "1")
 	pageSize := c.DefaultQuery("pageSize", "10")
+	cursor := c.Query("cursor")
+	limit := c.Query("limit")
 	filters := c.QueryArray("filters")
+	sort := c.Query("sort")
+	releasedAfter := c.Query("releasedAfter")
+	releasedBefore := c.Query("releasedBefore")
 
 	h.logger.Debug("Got req to get songs",
 		zap.String("page", page),
 		zap.String("pageSize", pageSize),
+		zap.String("cursor", cursor),
+		zap.String("limit", limit),
 		zap.Strings("filters", filters),
+		zap.String("sort", sort),
+		zap.String("releasedAfter", releasedAfter),
+		zap.String("releasedBefore", releasedBefore),
 		zap.String("traceparent",
 			c.Request.Header.Get("traceparent")))
 
 	request := &services.GetSongsRequest{
-		Page:     page,
-		PageSize: pageSize,
-		Filters:  filters,
+		Page:           page,
+		PageSize:       pageSize,
+		Cursor:         cursor,
+		Limit:          limit,
+		Filters:        filters,
+		Sort:           sort,
+		ReleasedAfter:  releasedAfter,
+		ReleasedBefore: releasedBefore,
 	}
 
 	response, err := h.client.GetSongs(request)
 	if err != nil {
-		h.logger.Error("Failed to get songs", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "Failed to get songs", err)
 		return
 	}
 
@@ -65,7 +83,37 @@ func (h *SongHandler) GetSongs(c *gin.Context) {
 		zap.Strings("filters", filters),
 		zap.String("traceparent", c.Request.Header.Get("traceparent")))
 
-	c.JSON(http.StatusOK, response.Songs)
+	c.JSON(http.StatusOK, response)
+}
+
+// GetSong godoc
+// @Summary Get a song
+// @Description Get a song by ID with its verse count and lyrics
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param songId path int true "Song ID"
+// @Success 200 {object} models.SongWithDetail
+// @Failure 404 {object} services.ResponseError
+// @Router /api/v1/songs/{songId} [get]
+func (h *SongHandler) GetSong(c *gin.Context) {
+	songId := c.Param("songId")
+
+	h.logger.Debug("Got req to get song",
+		zap.String("songId", songId),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	song, err := h.client.GetSong(&services.GetSongRequest{SongId: songId})
+	if err != nil {
+		respondError(c, h.logger, "Failed to get song", err)
+		return
+	}
+
+	h.logger.Debug("Get song request has ended successfully",
+		zap.String("songId", songId),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	c.JSON(http.StatusOK, song)
 }
 
 // GetSongText godoc
@@ -77,7 +125,8 @@ func (h *SongHandler) GetSongs(c *gin.Context) {
 // @Param songId path int true "Song ID"
 // @Param page query int false "Page number" default(1)
 // @Param pageSize query int false "Limit per page" default(10)
-// @Success 200 {array} models.Verse
+// @Success 200 {object} services.GetSongTextResponse
+// @Failure 404 {object} services.ResponseError
 // @Router /api/v1/songs/{songId}/text [get]
 func (h *SongHandler) GetSongText(c *gin.Context) {
 	songId := c.Param("songId")
@@ -98,8 +147,7 @@ func (h *SongHandler) GetSongText(c *gin.Context) {
 
 	response, err := h.client.GetSongText(request)
 	if err != nil {
-		h.logger.Error("Failed to get song text", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "Failed to get song text", err)
 		return
 	}
 
@@ -109,7 +157,93 @@ func (h *SongHandler) GetSongText(c *gin.Context) {
 		zap.String("pageSize", pageSize),
 		zap.String("traceparent", c.Request.Header.Get("traceparent")))
 
-	c.JSON(http.StatusOK, response.Verses)
+	c.JSON(http.StatusOK, response)
+}
+
+// SearchLyrics godoc
+// @Summary Search songs by lyrics
+// @Description Full-text search across verses, ranked by relevance, with the best matching verse of each song highlighted
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param q query string true "Search query, e.g. black hole"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Limit per page" default(10)
+// @Success 200 {object} services.SearchLyricsResponse
+// @Failure 400 {object} services.ResponseError
+// @Router /api/v1/search [get]
+func (h *SongHandler) SearchLyrics(c *gin.Context) {
+	q := c.Query("q")
+	page := c.DefaultQuery("page", "1")
+	pageSize := c.DefaultQuery("pageSize", "10")
+
+	h.logger.Debug("Got req to search lyrics",
+		zap.String("q", q),
+		zap.String("page", page),
+		zap.String("pageSize", pageSize),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	request := &services.SearchLyricsRequest{
+		Query:    q,
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	response, err := h.client.SearchLyrics(request)
+	if err != nil {
+		respondError(c, h.logger, "Failed to search lyrics", err)
+		return
+	}
+
+	h.logger.Debug("Search lyrics request has ended successfully",
+		zap.String("q", q),
+		zap.Int("results", len(response.Results)),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SearchSongs godoc
+// @Summary Fuzzy search songs by group and song name
+// @Description Typo-tolerant search over group and song names, ordered by trigram similarity score
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param q query string true "Search query, e.g. supermasive black hole"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Limit per page" default(10)
+// @Success 200 {object} services.SearchSongsResponse
+// @Failure 400 {object} services.ResponseError
+// @Router /api/v1/search/songs [get]
+func (h *SongHandler) SearchSongs(c *gin.Context) {
+	q := c.Query("q")
+	page := c.DefaultQuery("page", "1")
+	pageSize := c.DefaultQuery("pageSize", "10")
+
+	h.logger.Debug("Got req to search songs",
+		zap.String("q", q),
+		zap.String("page", page),
+		zap.String("pageSize", pageSize),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	request := &services.SearchSongsRequest{
+		Query:    q,
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	response, err := h.client.SearchSongs(request)
+	if err != nil {
+		respondError(c, h.logger, "Failed to search songs", err)
+		return
+	}
+
+	h.logger.Debug("Search songs request has ended successfully",
+		zap.String("q", q),
+		zap.Int("results", len(response.Results)),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	c.JSON(http.StatusOK, response)
 }
 
 // DeleteSong godoc
@@ -119,7 +253,13 @@ func (h *SongHandler) GetSongText(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param songId path int true "Song ID"
-// @Success 204
+// @Param X-Actor header string false "Who makes the change, recorded in the song history"
+// @Param Idempotency-Key header string false "Key that makes retries of this request return the first response"
+// @Success 202 {object} models.Job
+// @Header 202 {string} Location "/api/v1/jobs/{jobId}"
+// @Failure 404 {object} services.ResponseError
+// @Failure 409 {object} services.ResponseError
+// @Failure 422 {object} services.ResponseError
 // @Router /api/v1/songs/{songId} [delete]
 func (h *SongHandler) DeleteSong(c *gin.Context) {
 	songId := c.Param("songId")
@@ -130,11 +270,12 @@ func (h *SongHandler) DeleteSong(c *gin.Context) {
 
 	request := &services.DeleteSongRequest{
 		SongId: songId,
+		Caller: caller(c),
 	}
 
-	if err := h.client.DeleteSong(request); err != nil {
-		h.logger.Error("Failed to delete song", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	job, err := h.client.DeleteSong(request)
+	if err != nil {
+		respondError(c, h.logger, "Failed to delete song", err)
 		return
 	}
 
@@ -142,8 +283,7 @@ func (h *SongHandler) DeleteSong(c *gin.Context) {
 		zap.String("songId", songId),
 		zap.String("traceparent", c.Request.Header.Get("traceparent")))
 
-	h.cache.DeleteFromCache("song_" + songId)
-	c.Status(http.StatusNoContent)
+	h.accepted(c, job)
 }
 
 // UpdateSong godoc
@@ -154,7 +294,13 @@ func (h *SongHandler) DeleteSong(c *gin.Context) {
 // @Produce json
 // @Param songId path int true "Song ID"
 // @Param song body models.Song true "Song data"
-// @Success 200 {object} models.Song
+// @Param X-Actor header string false "Who makes the change, recorded in the song history"
+// @Param Idempotency-Key header string false "Key that makes retries of this request return the first response"
+// @Success 202 {object} models.Job
+// @Header 202 {string} Location "/api/v1/jobs/{jobId}"
+// @Failure 404 {object} services.ResponseError
+// @Failure 409 {object} services.ResponseError
+// @Failure 422 {object} services.ResponseError
 // @Router /api/v1/songs/{songId} [patch]
 func (h *SongHandler) UpdateSong(c *gin.Context) {
 	songId := c.Param("songId")
@@ -172,11 +318,12 @@ func (h *SongHandler) UpdateSong(c *gin.Context) {
 	request := &services.UpdateSongRequest{
 		SongID: songId,
 		Song:   song,
+		Caller: caller(c),
 	}
 
-	if err := h.client.UpdateSong(request); err != nil {
-		h.logger.Error("Failed to update song", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	job, err := h.client.UpdateSong(request)
+	if err != nil {
+		respondError(c, h.logger, "Failed to update song", err)
 		return
 	}
 
@@ -185,8 +332,7 @@ func (h *SongHandler) UpdateSong(c *gin.Context) {
 		zap.Any("song", song),
 		zap.String("traceparent", c.Request.Header.Get("traceparent")))
 
-	h.cache.DeleteFromCache("song_" + songId)
-	c.JSON(http.StatusOK, song)
+	h.accepted(c, job)
 }
 
 // AddSong godoc
@@ -196,7 +342,12 @@ func (h *SongHandler) UpdateSong(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param song body models.Song true "Song data"
-// @Success 204
+// @Param X-Actor header string false "Who makes the change, recorded in the song history"
+// @Param Idempotency-Key header string false "Key that makes retries of this request return the first response"
+// @Success 202 {object} models.Job
+// @Header 202 {string} Location "/api/v1/jobs/{jobId}"
+// @Failure 409 {object} services.ResponseError
+// @Failure 422 {object} services.ResponseError
 // @Router /api/v1/songs [post]
 func (h *SongHandler) AddSong(c *gin.Context) {
 	var song models.Song
@@ -210,12 +361,13 @@ func (h *SongHandler) AddSong(c *gin.Context) {
 		zap.String("traceparent", c.Request.Header.Get("traceparent")))
 
 	request := &services.AddSongRequest{
-		Song: song,
+		Song:   song,
+		Caller: caller(c),
 	}
 
-	if err := h.client.AddSong(request); err != nil {
-		h.logger.Error("Failed to add song to queue", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	job, err := h.client.AddSong(request)
+	if err != nil {
+		respondError(c, h.logger, "Failed to add song to queue", err)
 		return
 	}
 
@@ -223,6 +375,57 @@ func (h *SongHandler) AddSong(c *gin.Context) {
 		zap.Any("song", song),
 		zap.String("traceparent", c.Request.Header.Get("traceparent")))
 
-	h.cache.ClearCache()
-	c.Status(http.StatusNoContent)
+	h.accepted(c, job)
+}
+
+// RefreshSong godoc
+// @Summary Refresh song details
+// @Description Fetch the release date, link and lyrics of a song from the enrichers again
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param songId path int true "Song ID"
+// @Param X-Actor header string false "Who makes the change, recorded in the song history"
+// @Param Idempotency-Key header string false "Key that makes retries of this request return the first response"
+// @Success 202 {object} models.Job
+// @Header 202 {string} Location "/api/v1/jobs/{jobId}"
+// @Failure 404 {object} services.ResponseError
+// @Failure 409 {object} services.ResponseError
+// @Failure 422 {object} services.ResponseError
+// @Router /api/v1/songs/{songId}/refresh [post]
+func (h *SongHandler) RefreshSong(c *gin.Context) {
+	songId := c.Param("songId")
+
+	h.logger.Debug("Got req to refresh song",
+		zap.String("songId", songId),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	job, err := h.client.RefreshSong(&services.RefreshSongRequest{
+		SongID: songId,
+		Caller: caller(c),
+	})
+	if err != nil {
+		respondError(c, h.logger, "Failed to refresh song", err)
+		return
+	}
+
+	h.logger.Debug("Refresh song request has ended successfully",
+		zap.String("songId", songId),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	h.accepted(c, job)
+}
+
+func (h *SongHandler) accepted(c *gin.Context, job *models.Job) {
+	c.Header("Location", "/api/v1/jobs/"+job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// caller forwards who makes the request and its trace to the song service, which records them in the
+// song history.
+func caller(c *gin.Context) services.Caller {
+	return services.Caller{
+		Actor:       c.GetHeader(middleware.ActorHeader),
+		Traceparent: c.Request.Header.Get("traceparent"),
+	}
 }
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"github.com/SZabrodskii/music-library/utils/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type StatsHandler struct {
+	client *services.SongServiceClient
+	logger *zap.Logger
+}
+
+func NewStatsHandler(client *services.SongServiceClient, logger *zap.Logger) *StatsHandler {
+	return &StatsHandler{
+		client: client,
+		logger: logger,
+	}
+}
+
+// GetFacetStats godoc
+// @Summary Get song counts per facet
+// @Description Count the songs matching the filters per group, release year or genre, with their average number of verses
+// @Tags stats
+// @Accept json
+// @Produce json
+// @Param by query string true "Facet to bucket by" Enums(group, year, genre)
+// @Param filters query []string false "Filters in the form field:operator:value, e.g. group:eq:Muse or genre:in:rock,jazz" collectionFormat(multi)
+// @Param releasedAfter query string false "Only songs released on or after this date (YYYY-MM-DD or DD.MM.YYYY)"
+// @Param releasedBefore query string false "Only songs released on or before this date (YYYY-MM-DD or DD.MM.YYYY)"
+// @Success 200 {object} models.FacetStats
+// @Failure 400 {object} services.ResponseError
+// @Router /api/v1/stats/facets [get]
+func (h *StatsHandler) GetFacetStats(c *gin.Context) {
+	request := &services.GetFacetStatsRequest{
+		By:             c.Query("by"),
+		Filters:        c.QueryArray("filters"),
+		ReleasedAfter:  c.Query("releasedAfter"),
+		ReleasedBefore: c.Query("releasedBefore"),
+	}
+
+	h.logger.Debug("Got req to get facet stats",
+		zap.String("by", request.By),
+		zap.Strings("filters", request.Filters),
+		zap.String("releasedAfter", request.ReleasedAfter),
+		zap.String("releasedBefore", request.ReleasedBefore),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	stats, err := h.client.GetFacetStats(request)
+	if err != nil {
+		respondError(c, h.logger, "Failed to get facet stats", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
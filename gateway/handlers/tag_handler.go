@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"github.com/SZabrodskii/music-library/utils/providers"
+	"github.com/SZabrodskii/music-library/utils/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type TagHandler struct {
+	cache  *providers.CacheProvider
+	client *services.SongServiceClient
+	logger *zap.Logger
+}
+
+func NewTagHandler(cache *providers.CacheProvider, client *services.SongServiceClient, logger *zap.Logger) *TagHandler {
+	return &TagHandler{
+		cache:  cache,
+		client: client,
+		logger: logger,
+	}
+}
+
+// GetTags godoc
+// @Summary Get tags
+// @Description Get tags ordered by slug, with the number of songs carrying them
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param q query string false "Part of the tag name"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Limit per page" default(10)
+// @Success 200 {object} services.GetTagsResponse
+// @Router /api/v1/tags [get]
+func (h *TagHandler) GetTags(c *gin.Context) {
+	query := c.Query("q")
+	page := c.DefaultQuery("page", "1")
+	pageSize := c.DefaultQuery("pageSize", "10")
+
+	h.logger.Debug("Got req to get tags",
+		zap.String("q", query),
+		zap.String("page", page),
+		zap.String("pageSize", pageSize),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	response, err := h.client.GetTags(&services.GetTagsRequest{
+		Query:    query,
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		respondError(c, h.logger, "Failed to get tags", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// AttachTag godoc
+// @Summary Tag a song
+// @Description Tag a song, creating the tag if it does not exist yet; tagging it twice is a no-op
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param songId path int true "Song ID"
+// @Param tag path string true "Tag name"
+// @Param Idempotency-Key header string false "Key that makes retries of this request return the first response"
+// @Success 204
+// @Failure 400 {object} services.ResponseError
+// @Failure 404 {object} services.ResponseError
+// @Failure 409 {object} services.ResponseError
+// @Failure 422 {object} services.ResponseError
+// @Router /api/v1/songs/{songId}/tags/{tag} [put]
+func (h *TagHandler) AttachTag(c *gin.Context) {
+	request := &services.SongTagRequest{SongId: c.Param("songId"), Tag: c.Param("tag")}
+
+	h.logger.Debug("Got req to attach tag",
+		zap.String("songId", request.SongId),
+		zap.String("tag", request.Tag),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	if err := h.client.AttachTag(request); err != nil {
+		respondError(c, h.logger, "Failed to attach tag", err)
+		return
+	}
+
+	h.invalidate(request.SongId)
+	c.Status(http.StatusNoContent)
+}
+
+// DetachTag godoc
+// @Summary Untag a song
+// @Description Remove a tag from a song; the tag itself is kept
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param songId path int true "Song ID"
+// @Param tag path string true "Tag name"
+// @Param Idempotency-Key header string false "Key that makes retries of this request return the first response"
+// @Success 204
+// @Failure 404 {object} services.ResponseError
+// @Failure 409 {object} services.ResponseError
+// @Failure 422 {object} services.ResponseError
+// @Router /api/v1/songs/{songId}/tags/{tag} [delete]
+func (h *TagHandler) DetachTag(c *gin.Context) {
+	request := &services.SongTagRequest{SongId: c.Param("songId"), Tag: c.Param("tag")}
+
+	h.logger.Debug("Got req to detach tag",
+		zap.String("songId", request.SongId),
+		zap.String("tag", request.Tag),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	if err := h.client.DetachTag(request); err != nil {
+		respondError(c, h.logger, "Failed to detach tag", err)
+		return
+	}
+
+	h.invalidate(request.SongId)
+	c.Status(http.StatusNoContent)
+}
+
+func (h *TagHandler) invalidate(songId string) {
+	h.cache.DeleteFromCache("song:" + songId)
+	h.cache.DeleteByPrefix("songs:")
+	h.cache.DeleteByPrefix("tags:")
+	h.cache.DeleteByPrefix("stats:")
+}
@@ -20,6 +20,13 @@ func main() {
 			providers.NewRedisProviderConfig,
 			providers.NewRedisProvider,
 			handlers.NewSongHandler,
+			handlers.NewJobHandler,
+			handlers.NewArtistHandler,
+			handlers.NewAlbumHandler,
+			handlers.NewGenreHandler,
+			handlers.NewTagHandler,
+			handlers.NewStatsHandler,
+			handlers.NewRevisionHandler,
 			handlers.NewRouter,
 		),
 		fx.Invoke(startServer),
@@ -0,0 +1,114 @@
+// Command mock-info-api serves the song info API contract (GET /info?group=...&song=...) from fixture files,
+// with configurable latency and failure rates, for local development and tests.
+package main
+
+import (
+	"fmt"
+	"github.com/SZabrodskii/music-library/song-service/services"
+	"github.com/SZabrodskii/music-library/utils"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"github.com/SZabrodskii/music-library/utils/providers"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+type config struct {
+	Port            string
+	FixturesDir     string
+	Latency         time.Duration
+	LatencyJitter   time.Duration
+	ErrorPercent    int
+	NotFoundPercent int
+	GenerateUnknown bool
+}
+
+func newConfig() *config {
+	return &config{
+		Port:            utils.GetEnv("MOCK_INFO_API_PORT", "8082"),
+		FixturesDir:     utils.GetEnv("MOCK_INFO_API_FIXTURES_DIR", "fixtures"),
+		Latency:         time.Duration(utils.GetEnv("MOCK_INFO_API_LATENCY_MS", 0)) * time.Millisecond,
+		LatencyJitter:   time.Duration(utils.GetEnv("MOCK_INFO_API_LATENCY_JITTER_MS", 0)) * time.Millisecond,
+		ErrorPercent:    utils.GetEnv("MOCK_INFO_API_ERROR_PERCENT", 0),
+		NotFoundPercent: utils.GetEnv("MOCK_INFO_API_NOT_FOUND_PERCENT", 0),
+		GenerateUnknown: utils.GetEnv("MOCK_INFO_API_GENERATE_UNKNOWN", false),
+	}
+}
+
+type server struct {
+	logger *zap.Logger
+	config *config
+	songs  map[string]*models.SongDetail
+}
+
+func main() {
+	logger, err := providers.NewLogger(providers.NewLoggerProviderConfig())
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	config := newConfig()
+
+	fixtures, err := services.LoadSongFixtures(config.FixturesDir)
+	if err != nil {
+		logger.Fatal("Failed to load fixtures", zap.Error(err))
+	}
+	s := &server{logger: logger, config: config, songs: make(map[string]*models.SongDetail, len(fixtures))}
+	for _, fixture := range fixtures {
+		s.songs[models.NormalizedKey(fixture.Group, fixture.Song)] = &models.SongDetail{
+			ReleaseDate: fixture.ReleaseDate,
+			Text:        fixture.Text,
+			Link:        fixture.Link,
+		}
+	}
+	logger.Info("Loaded fixtures", zap.Int("songs", len(s.songs)), zap.String("dir", config.FixturesDir))
+
+	router := gin.Default()
+	router.GET("/info", s.info)
+	if err := router.Run(":" + config.Port); err != nil {
+		logger.Fatal("Failed to start server", zap.Error(err))
+	}
+}
+
+func (s *server) info(c *gin.Context) {
+	group, song := c.Query("group"), c.Query("song")
+	if group == "" || song == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "group and song are required"})
+		return
+	}
+
+	delay := s.config.Latency
+	if s.config.LatencyJitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(s.config.LatencyJitter) + 1))
+	}
+	select {
+	case <-c.Request.Context().Done():
+		return
+	case <-time.After(delay):
+	}
+
+	if rand.Intn(100) < s.config.ErrorPercent {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "injected failure"})
+		return
+	}
+
+	detail, ok := s.songs[models.NormalizedKey(group, song)]
+	if !ok && s.config.GenerateUnknown {
+		detail, ok = generate(group, song), true
+	}
+	if !ok || rand.Intn(100) < s.config.NotFoundPercent {
+		c.JSON(http.StatusNotFound, gin.H{"error": "song not found"})
+		return
+	}
+	c.JSON(http.StatusOK, detail)
+}
+
+func generate(group, song string) *models.SongDetail {
+	return &models.SongDetail{
+		ReleaseDate: time.Date(1970+rand.Intn(55), time.Month(1+rand.Intn(12)), 1+rand.Intn(28), 0, 0, 0, 0, time.UTC).Format("02.01.2006"),
+		Text:        fmt.Sprintf("%s by %s, verse one\n\n%s by %s, verse two", song, group, song, group),
+		Link:        fmt.Sprintf("https://example.com/songs/%d", rand.Intn(1000000)),
+	}
+}
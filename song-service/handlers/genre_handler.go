@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"errors"
+	internalServices "github.com/SZabrodskii/music-library/song-service/services"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"github.com/SZabrodskii/music-library/utils/providers"
+	"github.com/SZabrodskii/music-library/utils/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type GenreHandler struct {
+	cache   *providers.CacheProvider
+	service *internalServices.GenreService
+	logger  *zap.Logger
+}
+
+func NewGenreHandler(cache *providers.CacheProvider, service *internalServices.GenreService, logger *zap.Logger) *GenreHandler {
+	return &GenreHandler{
+		cache:   cache,
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetGenres godoc
+// @Summary Get genres
+// @Description Get the genre tree, each genre with the number of songs in it or its descendants
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Success 200 {object} services.GetGenresResponse
+// @Router /genres [get]
+func (h *GenreHandler) GetGenres(c *gin.Context) {
+	h.logger.Debug("Got req to get genres",
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	genres, err := h.service.GetGenres()
+	if err != nil {
+		h.respondError(c, "Failed to get genres", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, services.GetGenresResponse{Genres: genres})
+}
+
+// CreateGenre godoc
+// @Summary Create a genre
+// @Description Create a genre, optionally below a parent genre
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param genre body models.Genre true "Genre data"
+// @Success 201 {object} models.Genre
+// @Failure 400 {object} services.ResponseError
+// @Failure 409 {object} services.ResponseError
+// @Router /genres [post]
+func (h *GenreHandler) CreateGenre(c *gin.Context) {
+	var genre models.Genre
+	if err := c.ShouldBindJSON(&genre); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.logger.Debug("Got req to create genre",
+		zap.Any("genre", genre),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	created, err := h.service.CreateGenre(&genre)
+	if err != nil {
+		h.respondError(c, "Failed to create genre", err)
+		return
+	}
+
+	h.cache.DeleteByPrefix("genres:")
+	c.JSON(http.StatusCreated, created)
+}
+
+// AttachGenre godoc
+// @Summary Attach a genre to a song
+// @Description Put a song in a genre; attaching it twice is a no-op
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param songId path int true "Song ID"
+// @Param genre path string true "Genre slug or name"
+// @Success 204
+// @Failure 404 {object} services.ResponseError
+// @Router /songs/{songId}/genres/{genre} [put]
+func (h *GenreHandler) AttachGenre(c *gin.Context) {
+	songId, ok := pathID(c, "songId", internalServices.ErrSongNotFound)
+	if !ok {
+		return
+	}
+	genre := c.Param("genre")
+
+	h.logger.Debug("Got req to attach genre",
+		zap.String("songId", songId),
+		zap.String("genre", genre),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	if err := h.service.AttachGenre(songId, genre); err != nil {
+		h.respondError(c, "Failed to attach genre", err)
+		return
+	}
+
+	h.invalidate(songId)
+	c.Status(http.StatusNoContent)
+}
+
+// DetachGenre godoc
+// @Summary Detach a genre from a song
+// @Description Remove a song from a genre it was attached to
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param songId path int true "Song ID"
+// @Param genre path string true "Genre slug or name"
+// @Success 204
+// @Failure 404 {object} services.ResponseError
+// @Router /songs/{songId}/genres/{genre} [delete]
+func (h *GenreHandler) DetachGenre(c *gin.Context) {
+	songId, ok := pathID(c, "songId", internalServices.ErrSongNotFound)
+	if !ok {
+		return
+	}
+	genre := c.Param("genre")
+
+	h.logger.Debug("Got req to detach genre",
+		zap.String("songId", songId),
+		zap.String("genre", genre),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	if err := h.service.DetachGenre(songId, genre); err != nil {
+		h.respondError(c, "Failed to detach genre", err)
+		return
+	}
+
+	h.invalidate(songId)
+	c.Status(http.StatusNoContent)
+}
+
+// invalidate drops the cached song and the song lists, genre counts and stats that depend on its genres.
+func (h *GenreHandler) invalidate(songId string) {
+	h.cache.DeleteFromCache("song:" + songId)
+	h.cache.DeleteByPrefix("songs:")
+	h.cache.DeleteByPrefix("genres:")
+	h.cache.DeleteByPrefix("stats:")
+}
+
+func (h *GenreHandler) respondError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, internalServices.ErrSongNotFound), errors.Is(err, internalServices.ErrGenreNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, internalServices.ErrInvalidGenre):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, internalServices.ErrGenreExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error(message, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
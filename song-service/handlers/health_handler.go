@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"github.com/SZabrodskii/music-library/utils/providers"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+type HealthHandler struct {
+	db    *gorm.DB
+	queue *providers.RabbitMQProvider
+}
+
+func NewHealthHandler(db *gorm.DB, queue *providers.RabbitMQProvider) *HealthHandler {
+	return &HealthHandler{
+		db:    db,
+		queue: queue,
+	}
+}
+
+// Health godoc
+// @Summary Health check
+// @Description Report whether the database and RabbitMQ connections are up
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /health [get]
+func (h *HealthHandler) Health(c *gin.Context) {
+	status := http.StatusOK
+	database := "up"
+	sqlDB, err := h.db.DB()
+	if err != nil || sqlDB.PingContext(c.Request.Context()) != nil {
+		database = "down"
+		status = http.StatusServiceUnavailable
+	}
+	rabbitmq := "up"
+	if !h.queue.IsConnected() {
+		rabbitmq = "down"
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, gin.H{"database": database, "rabbitmq": rabbitmq})
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"errors"
+	internalServices "github.com/SZabrodskii/music-library/song-service/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type JobHandler struct {
+	service *internalServices.JobService
+	logger  *zap.Logger
+}
+
+func NewJobHandler(service *internalServices.JobService, logger *zap.Logger) *JobHandler {
+	return &JobHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetJob godoc
+// @Summary Get job status
+// @Description Get the status of an asynchronous add, update, delete, refresh or revert song job
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Param jobId path string true "Job ID"
+// @Success 200 {object} models.Job
+// @Failure 404 {object} services.ResponseError
+// @Router /jobs/{jobId} [get]
+func (h *JobHandler) GetJob(c *gin.Context) {
+	jobId := c.Param("jobId")
+
+	h.logger.Debug("Got req to get job",
+		zap.String("jobId", jobId),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	job, err := h.service.GetJob(jobId)
+	if errors.Is(err, internalServices.ErrJobNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get job", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	internalServices "github.com/SZabrodskii/music-library/song-service/services"
+	"github.com/SZabrodskii/music-library/utils/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type RevisionHandler struct {
+	songs     *internalServices.SongService
+	revisions *internalServices.RevisionService
+	logger    *zap.Logger
+}
+
+func NewRevisionHandler(songs *internalServices.SongService, revisions *internalServices.RevisionService, logger *zap.Logger) *RevisionHandler {
+	return &RevisionHandler{
+		songs:     songs,
+		revisions: revisions,
+		logger:    logger,
+	}
+}
+
+// GetSongHistory godoc
+// @Summary Get song history
+// @Description Get the revisions of a song, newest first, each with a full snapshot, its diff from the previous revision, the actor and the trace ID
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param songId path int true "Song ID"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Limit per page" default(10)
+// @Success 200 {object} services.GetSongHistoryResponse
+// @Failure 404 {object} services.ResponseError
+// @Router /songs/{songId}/history [get]
+func (h *RevisionHandler) GetSongHistory(c *gin.Context) {
+	songId, ok := pathID(c, "songId", internalServices.ErrSongNotFound)
+	if !ok {
+		return
+	}
+	page := c.DefaultQuery("page", "1")
+	pageSize := c.DefaultQuery("pageSize", "10")
+
+	h.logger.Debug("Got req to get song history",
+		zap.String("songId", songId),
+		zap.String("page", page),
+		zap.String("pageSize", pageSize),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	revisions, meta, err := h.revisions.GetHistory(&internalServices.GetHistoryRequest{
+		SongID:   songId,
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if errors.Is(err, internalServices.ErrSongNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get song history", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, services.GetSongHistoryResponse{Revisions: revisions, Pagination: *meta})
+}
+
+// RevertSong godoc
+// @Summary Revert a song
+// @Description Restore the fields, verses and deletion of a song to a revision from its history; the result is recorded as a new revision
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param songId path int true "Song ID"
+// @Param revision path int true "Revision number"
+// @Success 202 {object} models.Job
+// @Header 202 {string} Location "/jobs/{jobId}"
+// @Failure 404 {object} services.ResponseError
+// @Router /songs/{songId}/revert/{revision} [post]
+func (h *RevisionHandler) RevertSong(c *gin.Context) {
+	songId, ok := pathID(c, "songId", internalServices.ErrSongNotFound)
+	if !ok {
+		return
+	}
+	revision, ok := pathID(c, "revision", internalServices.ErrRevisionNotFound)
+	if !ok {
+		return
+	}
+
+	h.logger.Debug("Got req to revert song",
+		zap.String("songId", songId),
+		zap.String("revision", revision),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	job, err := h.songs.RequestRevert(songId, revision, origin(c))
+	if errors.Is(err, internalServices.ErrSongNotFound) || errors.Is(err, internalServices.ErrRevisionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to enqueue revert song task", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Location", "/jobs/"+job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
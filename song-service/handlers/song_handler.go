@@ -4,27 +4,30 @@ import (
 	"context"
 	"errors"
 	internalServices "github.com/SZabrodskii/music-library/song-service/services"
+	"github.com/SZabrodskii/music-library/utils/filters"
 	"github.com/SZabrodskii/music-library/utils/middleware"
 	"github.com/SZabrodskii/music-library/utils/models"
+	"github.com/SZabrodskii/music-library/utils/pagination"
 	"github.com/SZabrodskii/music-library/utils/providers"
 	"github.com/SZabrodskii/music-library/utils/services"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type SongHandler struct {
-	cache   *providers.CacheProvider
 	service *internalServices.SongService
 	logger  *zap.Logger
 }
 
-func NewSongHandler(cache *providers.CacheProvider, service *internalServices.SongService, logger *zap.Logger) *SongHandler {
+func NewSongHandler(service *internalServices.SongService, logger *zap.Logger) *SongHandler {
 	return &SongHandler{
-		cache:   cache,
 		service: service,
 		logger:  logger,
 	}
@@ -38,38 +41,118 @@ func NewSongHandler(cache *providers.CacheProvider, service *internalServices.So
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param pageSize query int false "Limit per page" default(10)
-// @Param filters query []string false "Filters"
-// @Success 200 {array} models.Song
+// @Param cursor query string false "Opaque cursor from nextCursor; switches to keyset pagination"
+// @Param limit query int false "Limit per page in keyset pagination" default(10)
+// @Param filters query []string false "Filters in the form field:operator:value, e.g. group:eq:Muse or genre:in:rock,jazz" collectionFormat(multi)
+// @Param sort query string false "Comma-separated sort keys (id, group, song, releaseDate, createdAt), prefix with - for descending, e.g. group,-releaseDate"
+// @Param releasedAfter query string false "Only songs released on or after this date (YYYY-MM-DD or DD.MM.YYYY)"
+// @Param releasedBefore query string false "Only songs released on or before this date (YYYY-MM-DD or DD.MM.YYYY)"
+// @Success 200 {object} services.GetSongsResponse
+// @Failure 400 {object} services.ResponseError
 // @Router /songs [get]
 func (h *SongHandler) GetSongs(c *gin.Context) {
 	page := c.DefaultQuery("page", "1")
 	pageSize := c.DefaultQuery("pageSize", "10")
-	filters := c.QueryArray("filters")
+	cursor := c.Query("cursor")
+	limit := c.Query("limit")
+	rawFilters := c.QueryArray("filters")
+	rawSort := c.Query("sort")
+	releasedAfter := c.Query("releasedAfter")
+	releasedBefore := c.Query("releasedBefore")
 	h.logger.Debug("Got req to get songs",
 		zap.String("page", page),
 		zap.String("pageSize", pageSize),
-		zap.Strings("filters", filters),
+		zap.String("cursor", cursor),
+		zap.String("limit", limit),
+		zap.Strings("filters", rawFilters),
+		zap.String("sort", rawSort),
+		zap.String("releasedAfter", releasedAfter),
+		zap.String("releasedBefore", releasedBefore),
 		zap.String("traceparent",
 			c.Request.Header.Get("traceparent")))
 
+	parsedFilters, err := filters.ParseQuery(rawFilters, releasedAfter, releasedBefore)
+	if err != nil {
+		h.logger.Debug("Invalid filters", zap.Strings("filters", rawFilters), zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sort, err := filters.ParseSort(rawSort)
+	if err != nil {
+		h.logger.Debug("Invalid sort", zap.String("sort", rawSort), zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	request := &internalServices.GetSongsRequest{
 		Page:     page,
 		PageSize: pageSize,
-		Filters:  filters,
+		Cursor:   cursor,
+		Limit:    limit,
+		Filters:  parsedFilters,
+		Sort:     sort,
 	}
 
-	songs, err := h.service.GetSongs(request)
+	songs, meta, err := h.service.GetSongs(request)
+	if errors.Is(err, pagination.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		h.logger.Error("Failed to get songs", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	facets, err := h.service.GetSongFacets(parsedFilters)
+	if err != nil {
+		h.logger.Error("Failed to count song facets", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 	h.logger.Debug("Get songs request has ended successfully",
 		zap.String("page", page),
 		zap.String("pageSize", pageSize),
-		zap.Strings("filters", filters),
+		zap.Strings("filters", rawFilters),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+	c.JSON(http.StatusOK, services.GetSongsResponse{Songs: songs, Facets: facets, Pagination: *meta})
+}
+
+// GetSong godoc
+// @Summary Get a song
+// @Description Get a song by ID with its verse count and lyrics
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param songId path int true "Song ID"
+// @Success 200 {object} models.SongWithDetail
+// @Failure 404 {object} services.ResponseError
+// @Router /songs/{songId} [get]
+func (h *SongHandler) GetSong(c *gin.Context) {
+	songId, ok := pathID(c, "songId", internalServices.ErrSongNotFound)
+	if !ok {
+		return
+	}
+
+	h.logger.Debug("Got req to get song",
+		zap.String("songId", songId),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	song, err := h.service.GetSong(&internalServices.GetSongRequest{SongId: songId})
+	if errors.Is(err, internalServices.ErrSongNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get song", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.logger.Debug("Get song req has ended",
+		zap.String("songId", songId),
 		zap.String("traceparent", c.Request.Header.Get("traceparent")))
-	c.JSON(http.StatusOK, services.GetSongsResponse{Songs: songs})
+	c.JSON(http.StatusOK, song)
 }
 
 // GetSongText godoc
@@ -81,10 +164,14 @@ func (h *SongHandler) GetSongs(c *gin.Context) {
 // @Param songId path int true "Song ID"
 // @Param page query int false "Page number" default(1)
 // @Param pageSize query int false "Limit per page" default(10)
-// @Success 200 {array} models.Verse
+// @Success 200 {object} services.GetSongTextResponse
+// @Failure 404 {object} services.ResponseError
 // @Router /songs/{songId}/text [get]
 func (h *SongHandler) GetSongText(c *gin.Context) {
-	songId := c.Param("songId")
+	songId, ok := pathID(c, "songId", internalServices.ErrSongNotFound)
+	if !ok {
+		return
+	}
 	page := c.DefaultQuery("page", "1")
 	pageSize := c.DefaultQuery("pageSize", "10")
 
@@ -100,7 +187,11 @@ func (h *SongHandler) GetSongText(c *gin.Context) {
 		PageSize: pageSize,
 	}
 
-	verses, err := h.service.GetSongText(request)
+	verses, meta, err := h.service.GetSongText(request)
+	if errors.Is(err, internalServices.ErrSongNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		h.logger.Error("Failed to get song text", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -112,7 +203,103 @@ func (h *SongHandler) GetSongText(c *gin.Context) {
 		zap.String("page", page),
 		zap.String("pageSize", pageSize),
 		zap.String("traceparent", c.Request.Header.Get("traceparent")))
-	c.JSON(http.StatusOK, verses)
+	c.JSON(http.StatusOK, services.GetSongTextResponse{Verses: verses, Pagination: *meta})
+}
+
+// SearchLyrics godoc
+// @Summary Search songs by lyrics
+// @Description Full-text search across verses, ranked by relevance, with the best matching verse of each song highlighted
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param q query string true "Search query, e.g. black hole"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Limit per page" default(10)
+// @Success 200 {object} services.SearchLyricsResponse
+// @Failure 400 {object} services.ResponseError
+// @Router /search [get]
+func (h *SongHandler) SearchLyrics(c *gin.Context) {
+	q := c.Query("q")
+	page := c.DefaultQuery("page", "1")
+	pageSize := c.DefaultQuery("pageSize", "10")
+
+	h.logger.Debug("Got req to search lyrics",
+		zap.String("q", q),
+		zap.String("page", page),
+		zap.String("pageSize", pageSize),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	if strings.TrimSpace(q) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "query parameter q is required"})
+		return
+	}
+
+	request := &internalServices.SearchLyricsRequest{
+		Query:    q,
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	matches, meta, err := h.service.SearchLyrics(request)
+	if err != nil {
+		h.logger.Error("Failed to search lyrics", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.logger.Debug("Search lyrics req has ended",
+		zap.String("q", q),
+		zap.Int("results", len(matches)),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+	c.JSON(http.StatusOK, services.SearchLyricsResponse{Results: matches, Pagination: *meta})
+}
+
+// SearchSongs godoc
+// @Summary Fuzzy search songs by group and song name
+// @Description Typo-tolerant search over group and song names, ordered by trigram similarity score
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param q query string true "Search query, e.g. supermasive black hole"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Limit per page" default(10)
+// @Success 200 {object} services.SearchSongsResponse
+// @Failure 400 {object} services.ResponseError
+// @Router /search/songs [get]
+func (h *SongHandler) SearchSongs(c *gin.Context) {
+	q := c.Query("q")
+	page := c.DefaultQuery("page", "1")
+	pageSize := c.DefaultQuery("pageSize", "10")
+
+	h.logger.Debug("Got req to search songs",
+		zap.String("q", q),
+		zap.String("page", page),
+		zap.String("pageSize", pageSize),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	if strings.TrimSpace(q) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "query parameter q is required"})
+		return
+	}
+
+	request := &internalServices.SearchSongsRequest{
+		Query:    q,
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	matches, meta, err := h.service.SearchSongs(request)
+	if err != nil {
+		h.logger.Error("Failed to search songs", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.logger.Debug("Search songs req has ended",
+		zap.String("q", q),
+		zap.Int("results", len(matches)),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+	c.JSON(http.StatusOK, services.SearchSongsResponse{Results: matches, Pagination: *meta})
 }
 
 // DeleteSong godoc
@@ -122,21 +309,27 @@ func (h *SongHandler) GetSongText(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param songId path int true "Song ID"
-// @Success 204
+// @Success 202 {object} models.Job
+// @Header 202 {string} Location "/jobs/{jobId}"
+// @Failure 404 {object} services.ResponseError
 // @Router /songs/{songId} [delete]
 func (h *SongHandler) DeleteSong(c *gin.Context) {
-	songId := c.Param("songId")
+	songId, ok := pathID(c, "songId", internalServices.ErrSongNotFound)
+	if !ok {
+		return
+	}
 
 	h.logger.Debug("Got req to delete song",
 		zap.String("songId", songId),
 		zap.String("traceparent", c.Request.Header.Get("traceparent")))
 
-	request := &internalServices.DeleteSongRequest{
-		SongId: songId,
+	job, err := h.service.RequestDelete(songId, origin(c))
+	if errors.Is(err, internalServices.ErrSongNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
 	}
-
-	if err := h.service.PublishToQueue("delete_song_queue", request); err != nil {
-		h.logger.Error("Failed to publish delete song task", zap.Error(err))
+	if err != nil {
+		h.logger.Error("Failed to enqueue delete song task", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -145,8 +338,7 @@ func (h *SongHandler) DeleteSong(c *gin.Context) {
 		zap.String("songId", songId),
 		zap.String("traceparent", c.Request.Header.Get("traceparent")))
 
-	h.cache.DeleteFromCache("song_" + songId)
-	c.Status(http.StatusNoContent)
+	h.accepted(c, job)
 }
 
 // UpdateSong godoc
@@ -157,10 +349,16 @@ func (h *SongHandler) DeleteSong(c *gin.Context) {
 // @Produce json
 // @Param songId path int true "Song ID"
 // @Param song body models.Song true "Song data"
-// @Success 200 {object} models.Song
+// @Success 202 {object} models.Job
+// @Header 202 {string} Location "/jobs/{jobId}"
+// @Failure 404 {object} services.ResponseError
+// @Failure 409 {object} services.ResponseError
 // @Router /songs/{songId} [patch]
 func (h *SongHandler) UpdateSong(c *gin.Context) {
-	songId := c.Param("songId")
+	songId, ok := pathID(c, "songId", internalServices.ErrSongNotFound)
+	if !ok {
+		return
+	}
 	var song models.Song
 	if err := c.ShouldBindJSON(&song); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -172,13 +370,30 @@ func (h *SongHandler) UpdateSong(c *gin.Context) {
 		zap.Any("song", song),
 		zap.String("traceparent", c.Request.Header.Get("traceparent")))
 
-	request := &internalServices.UpdateSongRequest{
-		SongID: songId,
-		Song:   &song,
+	if err := internalServices.ValidateCredits(song.Artists); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	if err := h.service.PublishToQueue("update_song_queue", request); err != nil {
-		h.logger.Error("Failed to publish update song task", zap.Error(err))
+	err := h.service.CheckDuplicate(songId, &song)
+	if errors.Is(err, internalServices.ErrSongNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if h.conflict(c, err) {
+		return
+	}
+
+	job, err := h.service.Enqueue(models.JobTypeUpdateSong, "update_song_queue", func(jobID string) interface{} {
+		return &internalServices.UpdateSongRequest{
+			JobID:  jobID,
+			SongID: songId,
+			Song:   &song,
+			Origin: origin(c),
+		}
+	})
+	if err != nil {
+		h.logger.Error("Failed to enqueue update song task", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -188,8 +403,7 @@ func (h *SongHandler) UpdateSong(c *gin.Context) {
 		zap.Any("song", song),
 		zap.String("traceparent", c.Request.Header.Get("traceparent")))
 
-	h.cache.DeleteFromCache("song_" + songId)
-	c.JSON(http.StatusOK, song)
+	h.accepted(c, job)
 }
 
 // AddSong godoc
@@ -199,7 +413,9 @@ func (h *SongHandler) UpdateSong(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param song body models.Song true "Song data"
-// @Success 204
+// @Success 202 {object} models.Job
+// @Header 202 {string} Location "/jobs/{jobId}"
+// @Failure 409 {object} services.ResponseError
 // @Router /songs [post]
 func (h *SongHandler) AddSong(c *gin.Context) {
 	var song models.Song
@@ -212,12 +428,24 @@ func (h *SongHandler) AddSong(c *gin.Context) {
 		zap.Any("song", song),
 		zap.String("traceparent", c.Request.Header.Get("traceparent")))
 
-	request := &internalServices.AddSongRequest{
-		Song: &song,
+	if err := internalServices.ValidateCredits(song.Artists); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if h.conflict(c, h.service.CheckDuplicate("", &song)) {
+		return
 	}
 
-	if err := h.service.AddSongToQueue(request); err != nil {
-		h.logger.Error("Failed to add song to queue", zap.Error(err))
+	job, err := h.service.Enqueue(models.JobTypeAddSong, "add_song_queue", func(jobID string) interface{} {
+		return &internalServices.AddSongRequest{
+			JobID:  jobID,
+			Song:   &song,
+			Origin: origin(c),
+		}
+	})
+	if err != nil {
+		h.logger.Error("Failed to enqueue add song task", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -226,17 +454,103 @@ func (h *SongHandler) AddSong(c *gin.Context) {
 		zap.Any("song", song),
 		zap.String("traceparent", c.Request.Header.Get("traceparent")))
 
-	h.cache.ClearCache()
-	c.Status(http.StatusNoContent)
+	h.accepted(c, job)
+}
+
+// RefreshSong godoc
+// @Summary Refresh song details
+// @Description Fetch the release date, link and lyrics of a song from the enrichers again
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param songId path int true "Song ID"
+// @Success 202 {object} models.Job
+// @Header 202 {string} Location "/jobs/{jobId}"
+// @Failure 404 {object} services.ResponseError
+// @Router /songs/{songId}/refresh [post]
+func (h *SongHandler) RefreshSong(c *gin.Context) {
+	songId, ok := pathID(c, "songId", internalServices.ErrSongNotFound)
+	if !ok {
+		return
+	}
+
+	h.logger.Debug("Got req to refresh song",
+		zap.String("songId", songId),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	job, err := h.service.RequestRefresh(songId, origin(c))
+	if errors.Is(err, internalServices.ErrSongNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to enqueue refresh song task", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.accepted(c, job)
+}
+
+// conflict answers 409 with the existing song's ID if err is a DuplicateSongError. Other errors are left
+// to the asynchronous job, which reports them.
+func (h *SongHandler) conflict(c *gin.Context, err error) bool {
+	var duplicate *internalServices.DuplicateSongError
+	if !errors.As(err, &duplicate) {
+		return false
+	}
+	c.JSON(http.StatusConflict, gin.H{"error": duplicate.Error(), "id": duplicate.ID})
+	return true
+}
+
+func (h *SongHandler) accepted(c *gin.Context, job *models.Job) {
+	c.Header("Location", "/jobs/"+job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// pathID returns the path parameter name if it is a numeric ID. Otherwise it answers 404 with notFound,
+// as no such record can exist, rather than letting the database reject the query.
+func pathID(c *gin.Context, name string, notFound error) (string, bool) {
+	id := c.Param(name)
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound.Error()})
+		return "", false
+	}
+	return id, true
+}
+
+// origin is who made the request and its trace, for the song revisions it causes.
+func origin(c *gin.Context) internalServices.Origin {
+	return internalServices.Origin{
+		Actor:   c.GetHeader(middleware.ActorHeader),
+		TraceID: middleware.TraceID(c.Request.Header.Get("traceparent")),
+	}
 }
 
 func RegisterHandlers(
 	logger *zap.Logger,
 	cache *providers.CacheProvider,
 	songService *internalServices.SongService,
+	jobService *internalServices.JobService,
+	artistService *internalServices.ArtistService,
+	albumService *internalServices.AlbumService,
+	genreService *internalServices.GenreService,
+	tagService *internalServices.TagService,
+	statsService *internalServices.StatsService,
+	revisionService *internalServices.RevisionService,
+	db *gorm.DB,
+	queue *providers.RabbitMQProvider,
 	lifecycle fx.Lifecycle,
 ) *gin.Engine {
-	handler := NewSongHandler(cache, songService, logger)
+	handler := NewSongHandler(songService, logger)
+	jobHandler := NewJobHandler(jobService, logger)
+	artistHandler := NewArtistHandler(artistService, logger)
+	albumHandler := NewAlbumHandler(cache, albumService, logger)
+	genreHandler := NewGenreHandler(cache, genreService, logger)
+	tagHandler := NewTagHandler(cache, tagService, logger)
+	statsHandler := NewStatsHandler(statsService, logger)
+	revisionHandler := NewRevisionHandler(songService, revisionService, logger)
+	healthHandler := NewHealthHandler(db, queue)
 	router := gin.New()
 	router.Use(middleware.TraceParentMiddleware())
 	router.Use(gin.Recovery())
@@ -255,19 +569,45 @@ func RegisterHandlers(
 	router.Use(middleware.CacheMiddleware(cache))
 
 	router.GET("/songs", handler.GetSongs)
+	router.GET("/songs/:songId", handler.GetSong)
 	router.GET("/songs/:songId/text", handler.GetSongText)
+	router.GET("/search", handler.SearchLyrics)
+	router.GET("/search/songs", handler.SearchSongs)
 	router.DELETE("/songs/:songId", handler.DeleteSong)
 	router.PATCH("/songs/:songId", handler.UpdateSong)
 	router.POST("/songs", handler.AddSong)
+	router.POST("/songs/:songId/refresh", handler.RefreshSong)
+	router.GET("/songs/:songId/history", revisionHandler.GetSongHistory)
+	router.POST("/songs/:songId/revert/:revision", revisionHandler.RevertSong)
+	router.GET("/jobs/:jobId", jobHandler.GetJob)
+	router.GET("/artists", artistHandler.GetArtists)
+	router.GET("/artists/:artistId", artistHandler.GetArtist)
+	router.GET("/albums", albumHandler.GetAlbums)
+	router.GET("/albums/:albumId", albumHandler.GetAlbum)
+	router.POST("/albums", albumHandler.CreateAlbum)
+	router.PATCH("/albums/:albumId", albumHandler.UpdateAlbum)
+	router.DELETE("/albums/:albumId", albumHandler.DeleteAlbum)
+	router.GET("/genres", genreHandler.GetGenres)
+	router.POST("/genres", genreHandler.CreateGenre)
+	router.PUT("/songs/:songId/genres/:genre", genreHandler.AttachGenre)
+	router.DELETE("/songs/:songId/genres/:genre", genreHandler.DetachGenre)
+	router.GET("/tags", tagHandler.GetTags)
+	router.PUT("/songs/:songId/tags/:tag", tagHandler.AttachTag)
+	router.DELETE("/songs/:songId/tags/:tag", tagHandler.DetachTag)
+	router.GET("/stats/facets", statsHandler.GetFacetStats)
+	router.GET("/health", healthHandler.Health)
 
 	lifecycle.Append(fx.Hook{
 		OnStart: func(context.Context) error {
 			songService.RegisterConsumers()
-			songService.ConsumerManager.StartConsumers()
+			if err := songService.ConsumerManager.StartConsumers(); err != nil {
+				return err
+			}
+			queue.OnReconnect(songService.ConsumerManager.StartConsumers)
 			return nil
 		},
 		OnStop: func(context.Context) error {
-			return nil
+			return queue.Close()
 		},
 	})
 
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"errors"
+	internalServices "github.com/SZabrodskii/music-library/song-service/services"
+	"github.com/SZabrodskii/music-library/utils/filters"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type StatsHandler struct {
+	service *internalServices.StatsService
+	logger  *zap.Logger
+}
+
+func NewStatsHandler(service *internalServices.StatsService, logger *zap.Logger) *StatsHandler {
+	return &StatsHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetFacetStats godoc
+// @Summary Get song counts per facet
+// @Description Count the songs matching the filters per group, release year or genre, with their average number of verses
+// @Tags stats
+// @Accept json
+// @Produce json
+// @Param by query string true "Facet to bucket by" Enums(group, year, genre)
+// @Param filters query []string false "Filters in the form field:operator:value, e.g. group:eq:Muse or genre:in:rock,jazz" collectionFormat(multi)
+// @Param releasedAfter query string false "Only songs released on or after this date (YYYY-MM-DD or DD.MM.YYYY)"
+// @Param releasedBefore query string false "Only songs released on or before this date (YYYY-MM-DD or DD.MM.YYYY)"
+// @Success 200 {object} models.FacetStats
+// @Failure 400 {object} services.ResponseError
+// @Router /stats/facets [get]
+func (h *StatsHandler) GetFacetStats(c *gin.Context) {
+	by := c.Query("by")
+	rawFilters := c.QueryArray("filters")
+	releasedAfter := c.Query("releasedAfter")
+	releasedBefore := c.Query("releasedBefore")
+
+	h.logger.Debug("Got req to get facet stats",
+		zap.String("by", by),
+		zap.Strings("filters", rawFilters),
+		zap.String("releasedAfter", releasedAfter),
+		zap.String("releasedBefore", releasedBefore),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	parsedFilters, err := filters.ParseQuery(rawFilters, releasedAfter, releasedBefore)
+	if err != nil {
+		h.logger.Debug("Invalid filters", zap.Strings("filters", rawFilters), zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stats, err := h.service.GetFacetStats(&internalServices.GetFacetStatsRequest{
+		By:      by,
+		Filters: parsedFilters,
+	})
+	if errors.Is(err, internalServices.ErrInvalidFacet) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get facet stats", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
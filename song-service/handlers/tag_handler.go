@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"errors"
+	internalServices "github.com/SZabrodskii/music-library/song-service/services"
+	"github.com/SZabrodskii/music-library/utils/providers"
+	"github.com/SZabrodskii/music-library/utils/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type TagHandler struct {
+	cache   *providers.CacheProvider
+	service *internalServices.TagService
+	logger  *zap.Logger
+}
+
+func NewTagHandler(cache *providers.CacheProvider, service *internalServices.TagService, logger *zap.Logger) *TagHandler {
+	return &TagHandler{
+		cache:   cache,
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetTags godoc
+// @Summary Get tags
+// @Description Get tags ordered by slug, with the number of songs carrying them
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param q query string false "Part of the tag name"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Limit per page" default(10)
+// @Success 200 {object} services.GetTagsResponse
+// @Router /tags [get]
+func (h *TagHandler) GetTags(c *gin.Context) {
+	query := c.Query("q")
+	page := c.DefaultQuery("page", "1")
+	pageSize := c.DefaultQuery("pageSize", "10")
+
+	h.logger.Debug("Got req to get tags",
+		zap.String("q", query),
+		zap.String("page", page),
+		zap.String("pageSize", pageSize),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	tags, meta, err := h.service.GetTags(&internalServices.GetTagsRequest{
+		Query:    query,
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		h.respondError(c, "Failed to get tags", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, services.GetTagsResponse{Tags: tags, Pagination: *meta})
+}
+
+// AttachTag godoc
+// @Summary Tag a song
+// @Description Tag a song, creating the tag if it does not exist yet; tagging it twice is a no-op
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param songId path int true "Song ID"
+// @Param tag path string true "Tag name"
+// @Success 204
+// @Failure 400 {object} services.ResponseError
+// @Failure 404 {object} services.ResponseError
+// @Router /songs/{songId}/tags/{tag} [put]
+func (h *TagHandler) AttachTag(c *gin.Context) {
+	songId, ok := pathID(c, "songId", internalServices.ErrSongNotFound)
+	if !ok {
+		return
+	}
+	tag := c.Param("tag")
+
+	h.logger.Debug("Got req to attach tag",
+		zap.String("songId", songId),
+		zap.String("tag", tag),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	if err := h.service.AttachTag(songId, tag); err != nil {
+		h.respondError(c, "Failed to attach tag", err)
+		return
+	}
+
+	h.invalidate(songId)
+	c.Status(http.StatusNoContent)
+}
+
+// DetachTag godoc
+// @Summary Untag a song
+// @Description Remove a tag from a song; the tag itself is kept
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param songId path int true "Song ID"
+// @Param tag path string true "Tag name"
+// @Success 204
+// @Failure 404 {object} services.ResponseError
+// @Router /songs/{songId}/tags/{tag} [delete]
+func (h *TagHandler) DetachTag(c *gin.Context) {
+	songId, ok := pathID(c, "songId", internalServices.ErrSongNotFound)
+	if !ok {
+		return
+	}
+	tag := c.Param("tag")
+
+	h.logger.Debug("Got req to detach tag",
+		zap.String("songId", songId),
+		zap.String("tag", tag),
+		zap.String("traceparent", c.Request.Header.Get("traceparent")))
+
+	if err := h.service.DetachTag(songId, tag); err != nil {
+		h.respondError(c, "Failed to detach tag", err)
+		return
+	}
+
+	h.invalidate(songId)
+	c.Status(http.StatusNoContent)
+}
+
+// invalidate drops the cached song and the song lists, tag counts and stats that depend on its tags.
+func (h *TagHandler) invalidate(songId string) {
+	h.cache.DeleteFromCache("song:" + songId)
+	h.cache.DeleteByPrefix("songs:")
+	h.cache.DeleteByPrefix("tags:")
+	h.cache.DeleteByPrefix("stats:")
+}
+
+func (h *TagHandler) respondError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, internalServices.ErrSongNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, internalServices.ErrInvalidTag):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error(message, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
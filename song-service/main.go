@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"github.com/SZabrodskii/music-library/song-service/handlers"
 	"github.com/SZabrodskii/music-library/song-service/migrations"
 	"github.com/SZabrodskii/music-library/song-service/services"
@@ -25,9 +26,23 @@ func main() {
 			providers.NewPostgresProviderConfig,
 			providers.NewPostgresProvider,
 			services.NewSongServiceConfig,
+			services.NewJobService,
+			services.NewConsumerManagerConfig,
+			services.NewOutboxServiceConfig,
+			services.NewOutboxService,
+			services.NewArtistService,
+			services.NewAlbumService,
+			services.NewGenreService,
+			services.NewTagService,
+			services.NewStatsService,
+			services.NewRevisionService,
+			services.NewSongInfoClientConfig,
+			services.NewSongInfoClient,
+			services.NewEnricherConfig,
+			services.NewEnricher,
 			services.NewSongService,
 		),
-		fx.Invoke(applyMigrations, handlers.RegisterHandlers),
+		fx.Invoke(applyMigrations, declareTopology, startOutboxRelay, startSongRefresh, handlers.RegisterHandlers),
 	)
 
 	app.Run()
@@ -38,3 +53,38 @@ func applyMigrations(db *gorm.DB) {
 		log.Fatalf("Failed to apply migrations: %v", err)
 	}
 }
+
+func declareTopology(queue *providers.RabbitMQProvider) {
+	if err := queue.DeclareTopology(); err != nil {
+		log.Fatalf("Failed to declare RabbitMQ topology: %v", err)
+	}
+}
+
+func startOutboxRelay(lifecycle fx.Lifecycle, outbox *services.OutboxService) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lifecycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go outbox.Run(ctx)
+			go outbox.RunPruning(ctx)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
+func startSongRefresh(lifecycle fx.Lifecycle, songService *services.SongService) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lifecycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go songService.RunRefresh(ctx)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
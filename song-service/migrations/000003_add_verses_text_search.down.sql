@@ -0,0 +1,3 @@
+-- song-service/migrations/000003_add_verses_text_search.down.sql
+DROP INDEX idx_verses_text_search;
+ALTER TABLE verses DROP COLUMN text_search;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- song-service/migrations/000003_add_verses_text_search.up.sql
+ALTER TABLE verses ADD COLUMN text_search tsvector GENERATED ALWAYS AS (to_tsvector('simple', text)) STORED;
+CREATE INDEX idx_verses_text_search ON verses USING GIN (text_search);
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- song-service/migrations/000004_add_songs_trigram_indexes.down.sql
+DROP INDEX idx_songs_full_name_trgm;
+DROP INDEX idx_songs_song_name_trgm;
+DROP INDEX idx_songs_group_name_trgm;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- song-service/migrations/000004_add_songs_trigram_indexes.up.sql
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX idx_songs_group_name_trgm ON songs USING GIN (group_name gin_trgm_ops);
+CREATE INDEX idx_songs_song_name_trgm ON songs USING GIN (song_name gin_trgm_ops);
+CREATE INDEX idx_songs_full_name_trgm ON songs USING GIN ((group_name || ' ' || song_name) gin_trgm_ops);
\ No newline at end of file
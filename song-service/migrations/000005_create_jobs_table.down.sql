@@ -0,0 +1,2 @@
+-- song-service/migrations/000005_create_jobs_table.down.sql
+DROP TABLE jobs;
\ No newline at end of file
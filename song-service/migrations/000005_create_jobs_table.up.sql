@@ -0,0 +1,12 @@
+-- song-service/migrations/000005_create_jobs_table.up.sql
+CREATE TABLE jobs (
+                      id UUID PRIMARY KEY,
+                      created_at TIMESTAMP NOT NULL,
+                      updated_at TIMESTAMP NOT NULL,
+                      type VARCHAR(32) NOT NULL,
+                      status VARCHAR(32) NOT NULL,
+                      error TEXT,
+                      song_id INT
+);
+
+CREATE INDEX idx_jobs_status ON jobs (status);
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- song-service/migrations/000006_create_outbox_messages_table.down.sql
+DROP TABLE outbox_messages;
\ No newline at end of file
@@ -0,0 +1,12 @@
+-- song-service/migrations/000006_create_outbox_messages_table.up.sql
+CREATE TABLE outbox_messages (
+                                 id BIGSERIAL PRIMARY KEY,
+                                 created_at TIMESTAMP NOT NULL,
+                                 queue VARCHAR(255) NOT NULL,
+                                 payload JSONB NOT NULL,
+                                 attempts INT NOT NULL DEFAULT 0,
+                                 last_error TEXT,
+                                 sent_at TIMESTAMP
+);
+
+CREATE INDEX idx_outbox_messages_pending ON outbox_messages (id) WHERE sent_at IS NULL;
\ No newline at end of file
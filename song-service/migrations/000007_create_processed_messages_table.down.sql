@@ -0,0 +1,4 @@
+-- song-service/migrations/000007_create_processed_messages_table.down.sql
+DROP TABLE processed_messages;
+
+ALTER TABLE outbox_messages DROP COLUMN message_id;
\ No newline at end of file
@@ -0,0 +1,8 @@
+-- song-service/migrations/000007_create_processed_messages_table.up.sql
+ALTER TABLE outbox_messages ADD COLUMN message_id UUID NOT NULL DEFAULT gen_random_uuid();
+
+CREATE TABLE processed_messages (
+                                    message_id VARCHAR(255) PRIMARY KEY,
+                                    queue VARCHAR(255) NOT NULL,
+                                    processed_at TIMESTAMP NOT NULL
+);
\ No newline at end of file
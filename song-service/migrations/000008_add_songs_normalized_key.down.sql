@@ -0,0 +1,10 @@
+-- song-service/migrations/000008_add_songs_normalized_key.down.sql
+DROP INDEX idx_songs_normalized_key;
+
+UPDATE songs
+SET deleted_at = NULL
+WHERE id IN (SELECT song_id FROM song_merges);
+
+DROP TABLE song_merges;
+
+ALTER TABLE songs DROP COLUMN normalized_key;
\ No newline at end of file
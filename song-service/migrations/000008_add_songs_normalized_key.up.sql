@@ -0,0 +1,36 @@
+-- song-service/migrations/000008_add_songs_normalized_key.up.sql
+ALTER TABLE songs ADD COLUMN normalized_key VARCHAR(511);
+
+UPDATE songs
+SET normalized_key = trim(regexp_replace(lower(group_name), '[^[:alnum:]]+', ' ', 'g'))
+    || '|' || trim(regexp_replace(lower(song_name), '[^[:alnum:]]+', ' ', 'g'));
+
+CREATE TABLE song_merges (
+                             song_id INT PRIMARY KEY,
+                             merged_into INT NOT NULL,
+                             merged_at TIMESTAMP NOT NULL,
+                             FOREIGN KEY (song_id) REFERENCES songs(id) ON DELETE CASCADE,
+                             FOREIGN KEY (merged_into) REFERENCES songs(id) ON DELETE CASCADE
+);
+
+INSERT INTO song_merges (song_id, merged_into, merged_at)
+SELECT id, first_id, NOW()
+FROM (
+         SELECT id, FIRST_VALUE(id) OVER (PARTITION BY normalized_key ORDER BY id) AS first_id
+         FROM songs
+         WHERE deleted_at IS NULL
+     ) ranked
+WHERE id <> first_id;
+
+UPDATE jobs
+SET song_id = song_merges.merged_into
+FROM song_merges
+WHERE jobs.song_id = song_merges.song_id;
+
+UPDATE songs
+SET deleted_at = NOW()
+WHERE id IN (SELECT song_id FROM song_merges);
+
+ALTER TABLE songs ALTER COLUMN normalized_key SET NOT NULL;
+
+CREATE UNIQUE INDEX idx_songs_normalized_key ON songs (normalized_key) WHERE deleted_at IS NULL;
\ No newline at end of file
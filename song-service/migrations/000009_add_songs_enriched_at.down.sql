@@ -0,0 +1,4 @@
+-- song-service/migrations/000009_add_songs_enriched_at.down.sql
+DROP INDEX idx_songs_enriched_at;
+
+ALTER TABLE songs DROP COLUMN enriched_at;
\ No newline at end of file
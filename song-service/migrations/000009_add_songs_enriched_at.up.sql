@@ -0,0 +1,6 @@
+-- song-service/migrations/000009_add_songs_enriched_at.up.sql
+ALTER TABLE songs ADD COLUMN enriched_at TIMESTAMP;
+
+UPDATE songs SET enriched_at = created_at;
+
+CREATE INDEX idx_songs_enriched_at ON songs (enriched_at) WHERE deleted_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- song-service/migrations/000010_convert_songs_release_date_to_date.down.sql
+DROP INDEX idx_songs_release_date;
+
+ALTER TABLE songs
+    ALTER COLUMN release_date TYPE VARCHAR(255) USING to_char(release_date, 'YYYY-MM-DD');
\ No newline at end of file
@@ -0,0 +1,21 @@
+-- song-service/migrations/000010_convert_songs_release_date_to_date.up.sql
+CREATE FUNCTION pg_temp.parse_release_date(value TEXT) RETURNS DATE AS $$
+BEGIN
+    value := trim(value);
+    RETURN CASE
+               WHEN value ~ '^\d{4}-\d{2}-\d{2}' THEN to_date(left(value, 10), 'YYYY-MM-DD')
+               WHEN value ~ '^\d{2}\.\d{2}\.\d{4}$' THEN to_date(value, 'DD.MM.YYYY')
+               WHEN value ~ '^\d{4}/\d{2}/\d{2}$' THEN to_date(value, 'YYYY/MM/DD')
+               WHEN value ~ '^\d{4}-\d{2}$' THEN to_date(value, 'YYYY-MM')
+               WHEN value ~ '^\d{4}$' THEN to_date(value, 'YYYY')
+        END;
+EXCEPTION
+    WHEN others THEN
+        RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE songs
+    ALTER COLUMN release_date TYPE DATE USING pg_temp.parse_release_date(release_date);
+
+CREATE INDEX idx_songs_release_date ON songs (release_date) WHERE deleted_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- song-service/migrations/000011_create_artists_tables.down.sql
+DROP TABLE song_artists;
+
+DROP TABLE artists;
\ No newline at end of file
@@ -0,0 +1,55 @@
+-- song-service/migrations/000011_create_artists_tables.up.sql
+CREATE TABLE artists (
+                         id SERIAL PRIMARY KEY,
+                         created_at TIMESTAMP NOT NULL,
+                         updated_at TIMESTAMP NOT NULL,
+                         name VARCHAR(255) NOT NULL,
+                         normalized_name VARCHAR(255) NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_artists_normalized_name ON artists (normalized_name);
+
+CREATE TABLE song_artists (
+                              song_id INT NOT NULL,
+                              artist_id INT NOT NULL,
+                              role VARCHAR(16) NOT NULL CHECK (role IN ('primary', 'featured', 'composer', 'lyricist')),
+                              position INT NOT NULL DEFAULT 0,
+                              PRIMARY KEY (song_id, artist_id, role),
+                              FOREIGN KEY (song_id) REFERENCES songs(id) ON DELETE CASCADE,
+                              FOREIGN KEY (artist_id) REFERENCES artists(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_song_artists_artist_id ON song_artists (artist_id);
+
+CREATE TEMPORARY TABLE song_credits AS
+WITH parts AS (
+    SELECT id, regexp_split_to_array(group_name, '\s+(?:feat\.?|ft\.?|featuring)\s+', 'i') AS names
+    FROM songs
+    WHERE id NOT IN (SELECT song_id FROM song_merges)
+),
+     credits AS (
+         SELECT id AS song_id, trim(names[1]) AS name, 'primary' AS role, 0 AS position
+         FROM parts
+         UNION ALL
+         SELECT parts.id, trim(featured.name), 'featured', featured.position
+         FROM parts,
+              regexp_split_to_table(array_to_string(names[2:], ', '), '\s*(?:,|&)\s*') WITH ORDINALITY AS featured(name, position)
+         WHERE array_length(names, 1) > 1
+     )
+SELECT song_id, name, role, position, trim(regexp_replace(lower(name), '[^[:alnum:]]+', ' ', 'g')) AS normalized_name
+FROM credits
+WHERE name <> '';
+
+INSERT INTO artists (created_at, updated_at, name, normalized_name)
+SELECT DISTINCT ON (normalized_name) NOW(), NOW(), name, normalized_name
+FROM song_credits
+WHERE normalized_name <> ''
+ORDER BY normalized_name, song_id;
+
+INSERT INTO song_artists (song_id, artist_id, role, position)
+SELECT song_credits.song_id, artists.id, song_credits.role, MIN(song_credits.position)
+FROM song_credits
+         JOIN artists ON artists.normalized_name = song_credits.normalized_name
+GROUP BY song_credits.song_id, artists.id, song_credits.role;
+
+DROP TABLE song_credits;
\ No newline at end of file
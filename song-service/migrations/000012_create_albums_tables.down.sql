@@ -0,0 +1,4 @@
+-- song-service/migrations/000012_create_albums_tables.down.sql
+DROP TABLE album_tracks;
+
+DROP TABLE albums;
\ No newline at end of file
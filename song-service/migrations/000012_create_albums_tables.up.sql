@@ -0,0 +1,27 @@
+-- song-service/migrations/000012_create_albums_tables.up.sql
+CREATE TABLE albums (
+                        id SERIAL PRIMARY KEY,
+                        created_at TIMESTAMP NOT NULL,
+                        updated_at TIMESTAMP NOT NULL,
+                        deleted_at TIMESTAMP,
+                        title VARCHAR(255) NOT NULL,
+                        artist_id INT,
+                        release_date DATE,
+                        cover_url VARCHAR(1024),
+                        FOREIGN KEY (artist_id) REFERENCES artists(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_albums_artist_id ON albums (artist_id);
+
+CREATE TABLE album_tracks (
+                              album_id INT NOT NULL,
+                              song_id INT NOT NULL,
+                              disc INT NOT NULL DEFAULT 1 CHECK (disc > 0),
+                              track_number INT NOT NULL CHECK (track_number > 0),
+                              PRIMARY KEY (album_id, song_id),
+                              UNIQUE (album_id, disc, track_number),
+                              FOREIGN KEY (album_id) REFERENCES albums(id) ON DELETE CASCADE,
+                              FOREIGN KEY (song_id) REFERENCES songs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_album_tracks_song_id ON album_tracks (song_id);
\ No newline at end of file
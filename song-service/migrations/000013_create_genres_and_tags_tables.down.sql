@@ -0,0 +1,8 @@
+-- song-service/migrations/000013_create_genres_and_tags_tables.down.sql
+DROP TABLE song_tags;
+
+DROP TABLE tags;
+
+DROP TABLE song_genres;
+
+DROP TABLE genres;
\ No newline at end of file
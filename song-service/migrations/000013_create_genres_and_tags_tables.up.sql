@@ -0,0 +1,43 @@
+-- song-service/migrations/000013_create_genres_and_tags_tables.up.sql
+CREATE TABLE genres (
+                        id SERIAL PRIMARY KEY,
+                        created_at TIMESTAMP NOT NULL,
+                        updated_at TIMESTAMP NOT NULL,
+                        name VARCHAR(255) NOT NULL,
+                        slug VARCHAR(255) NOT NULL,
+                        parent_id INT CHECK (parent_id <> id),
+                        FOREIGN KEY (parent_id) REFERENCES genres(id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX idx_genres_slug ON genres (slug);
+CREATE INDEX idx_genres_parent_id ON genres (parent_id);
+
+CREATE TABLE song_genres (
+                             song_id INT NOT NULL,
+                             genre_id INT NOT NULL,
+                             PRIMARY KEY (song_id, genre_id),
+                             FOREIGN KEY (song_id) REFERENCES songs(id) ON DELETE CASCADE,
+                             FOREIGN KEY (genre_id) REFERENCES genres(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_song_genres_genre_id ON song_genres (genre_id);
+
+CREATE TABLE tags (
+                      id SERIAL PRIMARY KEY,
+                      created_at TIMESTAMP NOT NULL,
+                      updated_at TIMESTAMP NOT NULL,
+                      name VARCHAR(255) NOT NULL,
+                      slug VARCHAR(255) NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_tags_slug ON tags (slug);
+
+CREATE TABLE song_tags (
+                           song_id INT NOT NULL,
+                           tag_id INT NOT NULL,
+                           PRIMARY KEY (song_id, tag_id),
+                           FOREIGN KEY (song_id) REFERENCES songs(id) ON DELETE CASCADE,
+                           FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_song_tags_tag_id ON song_tags (tag_id);
\ No newline at end of file
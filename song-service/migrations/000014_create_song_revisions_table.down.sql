@@ -0,0 +1,2 @@
+-- song-service/migrations/000014_create_song_revisions_table.down.sql
+DROP TABLE song_revisions;
\ No newline at end of file
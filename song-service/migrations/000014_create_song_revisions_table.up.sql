@@ -0,0 +1,33 @@
+-- song-service/migrations/000014_create_song_revisions_table.up.sql
+CREATE TABLE song_revisions (
+                                id SERIAL PRIMARY KEY,
+                                created_at TIMESTAMP NOT NULL,
+                                song_id INT NOT NULL,
+                                revision INT NOT NULL,
+                                action VARCHAR(16) NOT NULL CHECK (action IN ('create', 'update', 'delete', 'refresh', 'revert')),
+                                snapshot JSONB NOT NULL,
+                                diff JSONB NOT NULL,
+                                actor VARCHAR(255) NOT NULL,
+                                trace_id VARCHAR(64),
+                                FOREIGN KEY (song_id) REFERENCES songs(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_song_revisions_song_id_revision ON song_revisions (song_id, revision);
+
+INSERT INTO song_revisions (created_at, song_id, revision, action, snapshot, diff, actor)
+SELECT songs.created_at,
+       songs.id,
+       1,
+       'create',
+       jsonb_build_object(
+               'group', songs.group_name,
+               'song', songs.song_name,
+               'releaseDate', songs.release_date,
+               'link', COALESCE(songs.link, ''),
+               'verses', COALESCE((SELECT jsonb_agg(verses.text ORDER BY verses.id)
+                                   FROM verses
+                                   WHERE verses.song_id = songs.id AND verses.deleted_at IS NULL), '[]'::jsonb))
+           || CASE WHEN songs.deleted_at IS NULL THEN '{}'::jsonb ELSE '{"deleted": true}'::jsonb END,
+       '{}'::jsonb,
+       'migration'
+FROM songs;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- song-service/migrations/000015_index_processed_messages_processed_at.down.sql
+DROP INDEX idx_processed_messages_processed_at;
\ No newline at end of file
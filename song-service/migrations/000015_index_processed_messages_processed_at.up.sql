@@ -0,0 +1,2 @@
+-- song-service/migrations/000015_index_processed_messages_processed_at.up.sql
+CREATE INDEX idx_processed_messages_processed_at ON processed_messages (processed_at);
\ No newline at end of file
@@ -0,0 +1,81 @@
+package migrations
+
+import (
+	"errors"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"os"
+	"reflect"
+	"testing"
+)
+
+var errRollback = errors.New("rollback")
+
+// TestNormalizedKeyMergesDuplicates runs 000008 against songs and jobs tables in a throwaway schema, inside a
+// transaction that is rolled back.
+func TestNormalizedKeyMergesDuplicates(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to connect to the test database: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	upSQL, err := os.ReadFile("000008_add_songs_normalized_key.up.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		setup := []string{
+			"CREATE SCHEMA merge_test",
+			"SET LOCAL search_path TO merge_test",
+			"CREATE TABLE songs (id SERIAL PRIMARY KEY, group_name VARCHAR(255), song_name VARCHAR(255), deleted_at TIMESTAMP)",
+			"CREATE TABLE jobs (id VARCHAR(36) PRIMARY KEY, song_id INT)",
+			`INSERT INTO songs (group_name, song_name, deleted_at) VALUES
+				('Muse', 'Uprising', NULL),
+				('muse ', 'uprising!', NULL),
+				('Muse', 'Hysteria', NULL),
+				('MUSE', 'Uprising', NOW())`,
+			"INSERT INTO jobs (id, song_id) VALUES ('first', 1), ('second', 2), ('third', 3)",
+		}
+		for _, statement := range setup {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Exec(string(upSQL)).Error; err != nil {
+			return err
+		}
+
+		var merges []struct {
+			SongID     uint
+			MergedInto uint
+		}
+		tx.Raw("SELECT song_id, merged_into FROM song_merges ORDER BY song_id").Scan(&merges)
+		if len(merges) != 1 || merges[0].SongID != 2 || merges[0].MergedInto != 1 {
+			t.Errorf("song_merges = %+v, want song 2 merged into 1", merges)
+		}
+		var jobSongs []uint
+		tx.Raw("SELECT song_id FROM jobs ORDER BY id").Scan(&jobSongs)
+		if want := []uint{1, 1, 3}; !reflect.DeepEqual(jobSongs, want) {
+			t.Errorf("job songs = %v, want %v", jobSongs, want)
+		}
+		var live []uint
+		tx.Raw("SELECT id FROM songs WHERE deleted_at IS NULL ORDER BY id").Scan(&live)
+		if want := []uint{1, 3}; !reflect.DeepEqual(live, want) {
+			t.Errorf("live songs = %v, want %v", live, want)
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("migration failed: %v", err)
+	}
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"github.com/SZabrodskii/music-library/utils/pagination"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"net/url"
+	"strconv"
+)
+
+var (
+	ErrAlbumNotFound = errors.New("album not found")
+	ErrInvalidAlbum  = errors.New("invalid album")
+)
+
+type AlbumService struct {
+	logger  *zap.Logger
+	db      *gorm.DB
+	artists *ArtistService
+}
+
+func NewAlbumService(logger *zap.Logger, db *gorm.DB, artists *ArtistService) *AlbumService {
+	return &AlbumService{
+		logger:  logger,
+		db:      db,
+		artists: artists,
+	}
+}
+
+type GetAlbumsRequest struct {
+	Page     string `json:"page"`
+	PageSize string `json:"pageSize"`
+}
+
+// GetAlbums lists albums by title, without their tracks.
+func (s *AlbumService) GetAlbums(req *GetAlbumsRequest) ([]*models.Album, *pagination.Pagination, error) {
+	albums := make([]*models.Album, 0)
+	query := s.db.Model(&models.Album{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, nil, err
+	}
+
+	page, pageSize := pagination.Normalize(req.Page, req.PageSize)
+	err := query.Order("title").Order("id").Offset(pagination.Offset(page, pageSize)).Limit(pageSize).Find(&albums).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.loadArtists(albums); err != nil {
+		return nil, nil, err
+	}
+
+	meta := pagination.New(total, page, pageSize, url.Values{})
+	return albums, &meta, nil
+}
+
+// GetAlbum returns an album with its tracks and their songs, ordered by disc and track number.
+func (s *AlbumService) GetAlbum(albumID string) (*models.Album, error) {
+	album, err := s.findAlbum(s.db, albumID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadArtists([]*models.Album{album}); err != nil {
+		return nil, err
+	}
+
+	var tracks []*models.AlbumTrack
+	err = s.db.Joins("JOIN songs ON songs.id = album_tracks.song_id AND songs.deleted_at IS NULL").
+		Where("album_tracks.album_id = ?", album.ID).
+		Order("album_tracks.disc").
+		Order("album_tracks.track_number").
+		Find(&tracks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	songIDs := make([]uint, 0, len(tracks))
+	for _, track := range tracks {
+		songIDs = append(songIDs, track.SongID)
+	}
+	var songs []*models.Song
+	if err := s.db.Where("id IN ?", songIDs).Find(&songs).Error; err != nil {
+		return nil, err
+	}
+	if err := s.artists.LoadCredits(s.db, songs); err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*models.Song, len(songs))
+	for _, song := range songs {
+		byID[song.ID] = song
+	}
+	for _, track := range tracks {
+		track.Song = byID[track.SongID]
+	}
+
+	album.Tracks = tracks
+	return album, nil
+}
+
+// CreateAlbum stores album with its tracks.
+func (s *AlbumService) CreateAlbum(album *models.Album) (*models.Album, error) {
+	if album.Title == "" {
+		return nil, fmt.Errorf("%w: title is required", ErrInvalidAlbum)
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.resolveArtist(tx, album); err != nil {
+			return err
+		}
+		created := &models.Album{
+			Title:       album.Title,
+			ArtistID:    album.ArtistID,
+			ReleaseDate: album.ReleaseDate,
+			CoverURL:    album.CoverURL,
+		}
+		if err := tx.Create(created).Error; err != nil {
+			return fmt.Errorf("failed to create album: %w", err)
+		}
+		album.ID = created.ID
+		return s.replaceTracks(tx, album.ID, album.Tracks)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetAlbum(strconv.FormatUint(uint64(album.ID), 10))
+}
+
+// UpdateAlbum applies the non-empty fields of patch to the album albumID. Tracks, if given, replace the
+// whole track listing; an empty list clears it.
+func (s *AlbumService) UpdateAlbum(albumID string, patch *models.Album) (*models.Album, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		album, err := s.findAlbum(tx, albumID)
+		if err != nil {
+			return err
+		}
+		if err := s.resolveArtist(tx, patch); err != nil {
+			return err
+		}
+		err = tx.Model(album).Updates(&models.Album{
+			Title:       patch.Title,
+			ArtistID:    patch.ArtistID,
+			ReleaseDate: patch.ReleaseDate,
+			CoverURL:    patch.CoverURL,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to update album: %w", err)
+		}
+		if patch.Tracks == nil {
+			return nil
+		}
+		return s.replaceTracks(tx, album.ID, patch.Tracks)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetAlbum(albumID)
+}
+
+func (s *AlbumService) DeleteAlbum(albumID string) error {
+	result := s.db.Where("id = ?", albumID).Delete(&models.Album{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAlbumNotFound
+	}
+	return nil
+}
+
+// SongAlbums returns the albums the song songID appears on, oldest first.
+func (s *AlbumService) SongAlbums(songID uint) ([]*models.SongAlbum, error) {
+	albums := make([]*models.SongAlbum, 0)
+	err := s.db.Table("album_tracks").
+		Select("albums.id AS album_id, albums.title, album_tracks.disc, album_tracks.track_number").
+		Joins("JOIN albums ON albums.id = album_tracks.album_id AND albums.deleted_at IS NULL").
+		Where("album_tracks.song_id = ?", songID).
+		Order("albums.release_date NULLS LAST").
+		Order("albums.id").
+		Scan(&albums).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find albums: %w", err)
+	}
+	return albums, nil
+}
+
+func (s *AlbumService) findAlbum(tx *gorm.DB, albumID string) (*models.Album, error) {
+	var album models.Album
+	if err := tx.Where("id = ?", albumID).First(&album).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAlbumNotFound
+		}
+		return nil, fmt.Errorf("failed to find album: %w", err)
+	}
+	return &album, nil
+}
+
+// resolveArtist sets the ArtistID of album from its artist name, creating the artist if needed.
+func (s *AlbumService) resolveArtist(tx *gorm.DB, album *models.Album) error {
+	if models.NormalizedArtistName(album.Artist) == "" {
+		return nil
+	}
+	artist, err := s.artists.resolveArtist(tx, album.Artist)
+	if err != nil {
+		return err
+	}
+	album.ArtistID = &artist.ID
+	return nil
+}
+
+func (s *AlbumService) replaceTracks(tx *gorm.DB, albumID uint, tracks []*models.AlbumTrack) error {
+	if err := tx.Where("album_id = ?", albumID).Delete(&models.AlbumTrack{}).Error; err != nil {
+		return fmt.Errorf("failed to remove tracks: %w", err)
+	}
+	if len(tracks) == 0 {
+		return nil
+	}
+
+	positions := make(map[[2]int]bool, len(tracks))
+	songIDs := make(map[uint]bool, len(tracks))
+	rows := make([]*models.AlbumTrack, 0, len(tracks))
+	for _, track := range tracks {
+		disc := max(track.Disc, 1)
+		if track.TrackNumber < 1 {
+			return fmt.Errorf("%w: song %d needs a track number", ErrInvalidAlbum, track.SongID)
+		}
+		position := [2]int{disc, track.TrackNumber}
+		if positions[position] {
+			return fmt.Errorf("%w: disc %d track %d is used more than once", ErrInvalidAlbum, disc, track.TrackNumber)
+		}
+		if songIDs[track.SongID] {
+			return fmt.Errorf("%w: song %d is listed more than once", ErrInvalidAlbum, track.SongID)
+		}
+		positions[position], songIDs[track.SongID] = true, true
+		rows = append(rows, &models.AlbumTrack{AlbumID: albumID, SongID: track.SongID, Disc: disc, TrackNumber: track.TrackNumber})
+	}
+
+	ids := make([]uint, 0, len(songIDs))
+	for id := range songIDs {
+		ids = append(ids, id)
+	}
+	var found int64
+	if err := tx.Model(&models.Song{}).Where("id IN ?", ids).Count(&found).Error; err != nil {
+		return fmt.Errorf("failed to find songs: %w", err)
+	}
+	if found != int64(len(ids)) {
+		return fmt.Errorf("%w: %w", ErrInvalidAlbum, ErrSongNotFound)
+	}
+
+	if err := tx.Create(&rows).Error; err != nil {
+		return fmt.Errorf("failed to create tracks: %w", err)
+	}
+	return nil
+}
+
+func (s *AlbumService) loadArtists(albums []*models.Album) error {
+	ids := make([]uint, 0, len(albums))
+	for _, album := range albums {
+		if album.ArtistID != nil {
+			ids = append(ids, *album.ArtistID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var artists []*models.Artist
+	if err := s.db.Where("id IN ?", ids).Find(&artists).Error; err != nil {
+		return fmt.Errorf("failed to find artists: %w", err)
+	}
+	names := make(map[uint]string, len(artists))
+	for _, artist := range artists {
+		names[artist.ID] = artist.Name
+	}
+	for _, album := range albums {
+		if album.ArtistID != nil {
+			album.Artist = names[*album.ArtistID]
+		}
+	}
+	return nil
+}
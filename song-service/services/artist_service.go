@@ -0,0 +1,210 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"github.com/SZabrodskii/music-library/utils/pagination"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net/url"
+)
+
+var ErrArtistNotFound = errors.New("artist not found")
+
+// songCountColumn counts the songs that are not deleted of each artist.
+const songCountColumn = `(SELECT COUNT(DISTINCT song_artists.song_id)
+    FROM song_artists
+             JOIN songs ON songs.id = song_artists.song_id AND songs.deleted_at IS NULL
+    WHERE song_artists.artist_id = artists.id) AS song_count`
+
+type ArtistService struct {
+	logger *zap.Logger
+	db     *gorm.DB
+}
+
+func NewArtistService(logger *zap.Logger, db *gorm.DB) *ArtistService {
+	return &ArtistService{
+		logger: logger,
+		db:     db,
+	}
+}
+
+type GetArtistsRequest struct {
+	Query    string `json:"q"`
+	Page     string `json:"page"`
+	PageSize string `json:"pageSize"`
+}
+
+// GetArtists lists artists by name, optionally only those whose name contains Query.
+func (s *ArtistService) GetArtists(req *GetArtistsRequest) ([]*models.Artist, *pagination.Pagination, error) {
+	artists := make([]*models.Artist, 0)
+	query := s.db.Model(&models.Artist{})
+	if name := models.NormalizedArtistName(req.Query); name != "" {
+		// Normalized names only hold letters, digits and spaces, so there is nothing to escape.
+		query = query.Where("artists.normalized_name LIKE ?", "%"+name+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, nil, err
+	}
+
+	page, pageSize := pagination.Normalize(req.Page, req.PageSize)
+	err := query.Select("artists.*, " + songCountColumn).
+		Order("artists.normalized_name").
+		Order("artists.id").
+		Offset(pagination.Offset(page, pageSize)).
+		Limit(pageSize).
+		Find(&artists).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	meta := pagination.New(total, page, pageSize, url.Values{"q": {req.Query}})
+	return artists, &meta, nil
+}
+
+type GetArtistRequest struct {
+	ArtistID string `json:"artistId"`
+}
+
+// GetArtist returns an artist with the songs they are credited on, ordered by song ID.
+func (s *ArtistService) GetArtist(req *GetArtistRequest) (*models.ArtistWithSongs, error) {
+	var artist models.Artist
+	if err := s.db.Select("artists.*, "+songCountColumn).Where("artists.id = ?", req.ArtistID).First(&artist).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrArtistNotFound
+		}
+		return nil, err
+	}
+
+	var credits []*models.SongArtist
+	err := s.db.Joins("JOIN songs ON songs.id = song_artists.song_id AND songs.deleted_at IS NULL").
+		Where("song_artists.artist_id = ?", artist.ID).
+		Order("song_artists.song_id").
+		Order("song_artists.role").
+		Find(&credits).Error
+	if err != nil {
+		return nil, err
+	}
+
+	songIDs := make([]uint, 0, len(credits))
+	for _, credit := range credits {
+		songIDs = append(songIDs, credit.SongID)
+	}
+	var songs []*models.Song
+	if err := s.db.Where("id IN ?", songIDs).Find(&songs).Error; err != nil {
+		return nil, err
+	}
+	if err := s.LoadCredits(s.db, songs); err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*models.Song, len(songs))
+	for _, song := range songs {
+		byID[song.ID] = song
+	}
+
+	result := &models.ArtistWithSongs{Artist: &artist, Songs: make([]*models.ArtistCredit, 0, len(credits))}
+	for _, credit := range credits {
+		result.Songs = append(result.Songs, &models.ArtistCredit{Song: byID[credit.SongID], Role: credit.Role})
+	}
+	return result, nil
+}
+
+// ValidateCredits checks the credits given with a song: each needs a name and a known role.
+func ValidateCredits(credits []*models.Credit) error {
+	for _, credit := range credits {
+		if models.NormalizedArtistName(credit.Name) == "" {
+			return errors.New("artist name is required")
+		}
+		if !credit.Role.Valid() {
+			return fmt.Errorf("unknown artist role %q", credit.Role)
+		}
+	}
+	return nil
+}
+
+// resolveArtist returns the artist named name, creating it if needed.
+func (s *ArtistService) resolveArtist(tx *gorm.DB, name string) (*models.Artist, error) {
+	artist := &models.Artist{Name: name, NormalizedName: models.NormalizedArtistName(name)}
+	err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "normalized_name"}}, DoNothing: true}).
+		Create(artist).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to create artist: %w", err)
+	}
+	if err := tx.Where("normalized_name = ?", artist.NormalizedName).First(artist).Error; err != nil {
+		return nil, fmt.Errorf("failed to find artist: %w", err)
+	}
+	return artist, nil
+}
+
+// CreditSong adds credits to the song songID, resolving artists by name. Existing credits are kept.
+func (s *ArtistService) CreditSong(tx *gorm.DB, songID uint, credits []*models.Credit) error {
+	positions := make(map[models.ArtistRole]int)
+	for _, credit := range credits {
+		artist, err := s.resolveArtist(tx, credit.Name)
+		if err != nil {
+			return err
+		}
+		err = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.SongArtist{
+			SongID:   songID,
+			ArtistID: artist.ID,
+			Role:     credit.Role,
+			Position: positions[credit.Role],
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to credit artist: %w", err)
+		}
+		positions[credit.Role]++
+	}
+	return nil
+}
+
+// ReplaceGroupCredits replaces the primary and featured artists of the song songID with those parsed from group.
+func (s *ArtistService) ReplaceGroupCredits(tx *gorm.DB, songID uint, group string) error {
+	err := tx.Where("song_id = ? AND role IN ?", songID, []models.ArtistRole{models.ArtistRolePrimary, models.ArtistRoleFeatured}).
+		Delete(&models.SongArtist{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to remove credits: %w", err)
+	}
+	return s.CreditSong(tx, songID, models.GroupCredits(group))
+}
+
+// LoadCredits fills the Artists of songs, ordered by role and position.
+func (s *ArtistService) LoadCredits(tx *gorm.DB, songs []*models.Song) error {
+	if len(songs) == 0 {
+		return nil
+	}
+	songIDs := make([]uint, 0, len(songs))
+	for _, song := range songs {
+		songIDs = append(songIDs, song.ID)
+	}
+
+	var rows []struct {
+		SongID   uint
+		ArtistID uint
+		Name     string
+		Role     models.ArtistRole
+	}
+	err := tx.Table("song_artists").
+		Select("song_artists.song_id, song_artists.artist_id, artists.name, song_artists.role").
+		Joins("JOIN artists ON artists.id = song_artists.artist_id").
+		Where("song_artists.song_id IN ?", songIDs).
+		Order(`song_artists.song_id, CASE song_artists.role
+    WHEN 'primary' THEN 0 WHEN 'featured' THEN 1 WHEN 'composer' THEN 2 ELSE 3 END, song_artists.position`).
+		Scan(&rows).Error
+	if err != nil {
+		return fmt.Errorf("failed to load credits: %w", err)
+	}
+
+	bySong := make(map[uint][]*models.Credit, len(songs))
+	for _, row := range rows {
+		bySong[row.SongID] = append(bySong[row.SongID], &models.Credit{ArtistID: row.ArtistID, Name: row.Name, Role: row.Role})
+	}
+	for _, song := range songs {
+		song.Artists = bySong[song.ID]
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// CircuitBreaker opens after threshold consecutive failures and rejects calls for openDuration. It then
+// turns half-open and lets a single trial call through: success closes it, failure opens it again.
+type CircuitBreaker struct {
+	mu           sync.Mutex
+	state        CircuitState
+	failures     int
+	trial        bool
+	threshold    int
+	openDuration time.Duration
+	onChange     []func(state CircuitState)
+}
+
+func NewCircuitBreaker(threshold int, openDuration time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		state:        CircuitClosed,
+		threshold:    max(threshold, 1),
+		openDuration: openDuration,
+	}
+}
+
+// OnChange registers fn to be called, outside the breaker's lock, on every state transition.
+func (b *CircuitBreaker) OnChange(fn func(state CircuitState)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onChange = append(b.onChange, fn)
+}
+
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Allow returns ErrCircuitOpen if the call must not be made.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case b.state == CircuitOpen, b.state == CircuitHalfOpen && b.trial:
+		return ErrCircuitOpen
+	case b.state == CircuitHalfOpen:
+		b.trial = true
+	}
+	return nil
+}
+
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	b.failures = 0
+	b.trial = false
+	changed := b.state != CircuitClosed
+	b.state = CircuitClosed
+	b.mu.Unlock()
+
+	if changed {
+		b.notify(CircuitClosed)
+	}
+}
+
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	b.failures++
+	b.trial = false
+	opened := b.state != CircuitOpen && (b.state == CircuitHalfOpen || b.failures >= b.threshold)
+	if opened {
+		b.state = CircuitOpen
+	}
+	b.mu.Unlock()
+
+	if opened {
+		b.notify(CircuitOpen)
+		time.AfterFunc(b.openDuration, b.halfOpen)
+	}
+}
+
+// Abandon ends a call without counting it, for calls the caller cancelled. A half-open breaker lets the
+// next call through as its trial.
+func (b *CircuitBreaker) Abandon() {
+	b.mu.Lock()
+	b.trial = false
+	b.mu.Unlock()
+}
+
+func (b *CircuitBreaker) halfOpen() {
+	b.mu.Lock()
+	changed := b.state == CircuitOpen
+	if changed {
+		b.state = CircuitHalfOpen
+	}
+	b.mu.Unlock()
+
+	if changed {
+		b.notify(CircuitHalfOpen)
+	}
+}
+
+func (b *CircuitBreaker) notify(state CircuitState) {
+	b.mu.Lock()
+	callbacks := b.onChange
+	b.mu.Unlock()
+	for _, fn := range callbacks {
+		fn(state)
+	}
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	type step struct {
+		// action is one of allow, success, failure, abandon, or wait, which waits until the breaker turns
+		// half-open.
+		action string
+		// wantState is the state after the action; for allow, wantOpen tells whether the call is rejected.
+		wantState CircuitState
+		wantOpen  bool
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "opens after threshold consecutive failures",
+			steps: []step{
+				{action: "failure", wantState: CircuitClosed},
+				{action: "failure", wantState: CircuitClosed},
+				{action: "allow", wantState: CircuitClosed},
+				{action: "failure", wantState: CircuitOpen},
+				{action: "allow", wantState: CircuitOpen, wantOpen: true},
+			},
+		},
+		{
+			name: "success resets the failure count",
+			steps: []step{
+				{action: "failure", wantState: CircuitClosed},
+				{action: "failure", wantState: CircuitClosed},
+				{action: "success", wantState: CircuitClosed},
+				{action: "failure", wantState: CircuitClosed},
+				{action: "failure", wantState: CircuitClosed},
+				{action: "allow", wantState: CircuitClosed},
+			},
+		},
+		{
+			name: "half-open lets a single trial through and success closes",
+			steps: []step{
+				{action: "failure"}, {action: "failure"}, {action: "failure", wantState: CircuitOpen},
+				{action: "wait", wantState: CircuitHalfOpen},
+				{action: "allow", wantState: CircuitHalfOpen},
+				{action: "allow", wantState: CircuitHalfOpen, wantOpen: true},
+				{action: "success", wantState: CircuitClosed},
+				{action: "allow", wantState: CircuitClosed},
+				{action: "failure", wantState: CircuitClosed},
+			},
+		},
+		{
+			name: "failed trial opens again",
+			steps: []step{
+				{action: "failure"}, {action: "failure"}, {action: "failure", wantState: CircuitOpen},
+				{action: "wait", wantState: CircuitHalfOpen},
+				{action: "allow", wantState: CircuitHalfOpen},
+				{action: "failure", wantState: CircuitOpen},
+				{action: "allow", wantState: CircuitOpen, wantOpen: true},
+				{action: "wait", wantState: CircuitHalfOpen},
+				{action: "allow", wantState: CircuitHalfOpen},
+			},
+		},
+		{
+			name: "abandoned trial lets the next call through",
+			steps: []step{
+				{action: "failure"}, {action: "failure"}, {action: "failure", wantState: CircuitOpen},
+				{action: "wait", wantState: CircuitHalfOpen},
+				{action: "allow", wantState: CircuitHalfOpen},
+				{action: "abandon", wantState: CircuitHalfOpen},
+				{action: "allow", wantState: CircuitHalfOpen},
+				{action: "success", wantState: CircuitClosed},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaker := NewCircuitBreaker(3, 10*time.Millisecond)
+			halfOpen := make(chan struct{}, 1)
+			breaker.OnChange(func(state CircuitState) {
+				if state == CircuitHalfOpen {
+					halfOpen <- struct{}{}
+				}
+			})
+
+			for i, s := range tt.steps {
+				switch s.action {
+				case "allow":
+					err := breaker.Allow()
+					if gotOpen := errors.Is(err, ErrCircuitOpen); gotOpen != s.wantOpen {
+						t.Fatalf("step %d: Allow() error = %v, want rejected %v", i, err, s.wantOpen)
+					}
+				case "success":
+					breaker.Success()
+				case "failure":
+					breaker.Failure()
+				case "abandon":
+					breaker.Abandon()
+				case "wait":
+					select {
+					case <-halfOpen:
+					case <-time.After(time.Second):
+						t.Fatalf("step %d: breaker did not turn half-open", i)
+					}
+				}
+				if s.wantState != "" && breaker.State() != s.wantState {
+					t.Fatalf("step %d (%s): state = %s, want %s", i, s.action, breaker.State(), s.wantState)
+				}
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerNotifiesTransitions(t *testing.T) {
+	breaker := NewCircuitBreaker(1, 10*time.Millisecond)
+	states := make(chan CircuitState, 10)
+	breaker.OnChange(func(state CircuitState) { states <- state })
+
+	breaker.Failure()
+	breaker.Failure()
+	next := func() CircuitState {
+		select {
+		case state := <-states:
+			return state
+		case <-time.After(time.Second):
+			t.Fatal("no state change notified")
+			return ""
+		}
+	}
+	for _, want := range []CircuitState{CircuitOpen, CircuitHalfOpen} {
+		if got := next(); got != want {
+			t.Fatalf("notified %s, want %s", got, want)
+		}
+	}
+	breaker.Success()
+	breaker.Success()
+	if got := next(); got != CircuitClosed {
+		t.Fatalf("notified %s, want %s", got, CircuitClosed)
+	}
+	select {
+	case state := <-states:
+		t.Fatalf("notified %s without a transition", state)
+	default:
+	}
+}
@@ -0,0 +1,338 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/SZabrodskii/music-library/utils"
+	"github.com/SZabrodskii/music-library/utils/providers"
+	"github.com/streadway/amqp"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RetryAttemptHeader carries the 1-based delivery attempt of a message; it is absent on the first delivery.
+const RetryAttemptHeader = "x-retry-attempt"
+
+// RetryPolicy describes how a failed message is redelivered: attempt n waits
+// InitialDelay * Multiplier^(n-1), capped at MaxDelay, and after MaxAttempts the message is dead-lettered.
+type RetryPolicy struct {
+	MaxAttempts  int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   int
+}
+
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	delay := p.InitialDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= time.Duration(p.Multiplier)
+	}
+	return min(delay, p.MaxDelay)
+}
+
+type ConsumerManagerConfig struct {
+	DefaultRetryPolicy    RetryPolicy
+	DefaultConsumeOptions providers.ConsumeOptions
+}
+
+func NewConsumerManagerConfig() *ConsumerManagerConfig {
+	return &ConsumerManagerConfig{
+		DefaultRetryPolicy: retryPolicyFromEnv("RETRY", RetryPolicy{
+			MaxAttempts:  5,
+			InitialDelay: time.Second,
+			MaxDelay:     time.Minute,
+			Multiplier:   2,
+		}),
+		DefaultConsumeOptions: consumeOptionsFromEnv("CONSUMER", providers.ConsumeOptions{
+			Prefetch:    10,
+			Concurrency: 1,
+		}),
+	}
+}
+
+// RetryPolicy returns the policy for queueName: the default one, overridden by variables prefixed
+// with the upper-cased queue name, e.g. ADD_SONG_QUEUE_RETRY_MAX_ATTEMPTS.
+func (c *ConsumerManagerConfig) RetryPolicy(queueName string) RetryPolicy {
+	return retryPolicyFromEnv(strings.ToUpper(queueName)+"_RETRY", c.DefaultRetryPolicy)
+}
+
+func retryPolicyFromEnv(prefix string, defaults RetryPolicy) RetryPolicy {
+	policy := RetryPolicy{
+		MaxAttempts:  utils.GetEnv(prefix+"_MAX_ATTEMPTS", defaults.MaxAttempts),
+		InitialDelay: time.Duration(utils.GetEnv(prefix+"_INITIAL_DELAY_MS", defaults.InitialDelay.Milliseconds())) * time.Millisecond,
+		MaxDelay:     time.Duration(utils.GetEnv(prefix+"_MAX_DELAY_MS", defaults.MaxDelay.Milliseconds())) * time.Millisecond,
+		Multiplier:   utils.GetEnv(prefix+"_MULTIPLIER", defaults.Multiplier),
+	}
+	policy.MaxAttempts = max(policy.MaxAttempts, 1)
+	policy.InitialDelay = max(policy.InitialDelay, time.Millisecond)
+	policy.MaxDelay = max(policy.MaxDelay, policy.InitialDelay)
+	policy.Multiplier = max(policy.Multiplier, 1)
+	return policy
+}
+
+// ConsumeOptions returns the prefetch and concurrency for queueName, overridable like the retry policy,
+// e.g. ADD_SONG_QUEUE_CONSUMER_CONCURRENCY.
+func (c *ConsumerManagerConfig) ConsumeOptions(queueName string) providers.ConsumeOptions {
+	return consumeOptionsFromEnv(strings.ToUpper(queueName)+"_CONSUMER", c.DefaultConsumeOptions)
+}
+
+func consumeOptionsFromEnv(prefix string, defaults providers.ConsumeOptions) providers.ConsumeOptions {
+	options := providers.ConsumeOptions{
+		Prefetch:    utils.GetEnv(prefix+"_PREFETCH", defaults.Prefetch),
+		Concurrency: utils.GetEnv(prefix+"_CONCURRENCY", defaults.Concurrency),
+	}
+	options.Concurrency = max(options.Concurrency, 1)
+	options.Prefetch = max(options.Prefetch, options.Concurrency)
+	return options
+}
+
+// permanentError marks a failure that retrying cannot fix; the message goes straight to the dead-letter queue.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+func permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+func isPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// deferredError marks a failure that says nothing about the message itself, e.g. an open circuit breaker;
+// the message is retried after the initial delay without using up one of its attempts.
+type deferredError struct {
+	err error
+}
+
+func (e *deferredError) Error() string {
+	return e.err.Error()
+}
+
+func (e *deferredError) Unwrap() error {
+	return e.err
+}
+
+func deferred(err error) error {
+	return &deferredError{err: err}
+}
+
+func isDeferred(err error) bool {
+	var d *deferredError
+	return errors.As(err, &d)
+}
+
+// MessageHandler processes a delivery. The consumer manager acknowledges the message when it returns nil,
+// schedules a delayed retry for other errors and dead-letters permanent errors or exhausted messages.
+type MessageHandler func(d amqp.Delivery) error
+
+type ConsumerManager struct {
+	queue    *providers.RabbitMQProvider
+	logger   *zap.Logger
+	db       *gorm.DB
+	config   *ConsumerManagerConfig
+	handlers map[string]MessageHandler
+	policies map[string]RetryPolicy
+
+	mu       sync.Mutex
+	channels map[string]*amqp.Channel
+	paused   map[string]bool
+}
+
+func NewConsumerManager(logger *zap.Logger, db *gorm.DB, queue *providers.RabbitMQProvider, config *ConsumerManagerConfig) *ConsumerManager {
+	return &ConsumerManager{
+		queue:    queue,
+		logger:   logger,
+		db:       db,
+		config:   config,
+		handlers: make(map[string]MessageHandler),
+		policies: make(map[string]RetryPolicy),
+		channels: make(map[string]*amqp.Channel),
+		paused:   make(map[string]bool),
+	}
+}
+
+func (cm *ConsumerManager) RegisterHandler(queueName string, handler MessageHandler) {
+	cm.handlers[queueName] = handler
+	cm.policies[queueName] = cm.config.RetryPolicy(queueName)
+}
+
+func (cm *ConsumerManager) StartConsumers() error {
+	for queueName := range cm.handlers {
+		policy := cm.policies[queueName]
+		// Deferred messages wait in the first delay queue even when the policy allows no retries.
+		for attempt := 1; attempt < max(policy.MaxAttempts, 2); attempt++ {
+			if _, err := cm.queue.DeclareDelayQueue(queueName, policy.Delay(attempt)); err != nil {
+				return err
+			}
+		}
+		if err := cm.startConsumer(queueName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cm *ConsumerManager) startConsumer(queueName string) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if cm.paused[queueName] {
+		cm.logger.Info("Consumer is paused", zap.String("queue", queueName))
+		return nil
+	}
+
+	options := cm.config.ConsumeOptions(queueName)
+	conn := cm.queue.GetConnection()
+	ch, err := cm.queue.Consume(queueName, options, cm.dispatch(queueName, cm.handlers[queueName]))
+	if err != nil {
+		return fmt.Errorf("failed to consume %s: %w", queueName, err)
+	}
+	cm.channels[queueName] = ch
+	go cm.watch(queueName, conn, ch)
+	cm.logger.Info("Started consumer", zap.String("queue", queueName), zap.Int("prefetch", options.Prefetch), zap.Int("concurrency", options.Concurrency))
+	return nil
+}
+
+// watch restarts the consumer of queueName when the broker closes its channel on its own, e.g. after a
+// channel exception or a consumer timeout. Channels closed by Pause are left alone, and so are those closed
+// with the connection, whose consumers are restarted by the reconnect hook.
+func (cm *ConsumerManager) watch(queueName string, conn *amqp.Connection, ch *amqp.Channel) {
+	err, ok := <-ch.NotifyClose(make(chan *amqp.Error, 1))
+	if !ok || err == nil {
+		return
+	}
+	cm.logger.Error("Consumer channel closed", zap.String("queue", queueName), zap.Error(err))
+
+	for delay := time.Second; ; delay = min(delay*2, 30*time.Second) {
+		cm.mu.Lock()
+		current := cm.channels[queueName] == ch
+		cm.mu.Unlock()
+		if !current || conn.IsClosed() {
+			return
+		}
+		err := cm.startConsumer(queueName)
+		if err == nil {
+			return
+		}
+		cm.logger.Error("Failed to restart consumer", zap.String("queue", queueName), zap.Duration("retryIn", delay), zap.Error(err))
+		time.Sleep(delay)
+	}
+}
+
+// Pause stops consuming queueName by closing its channel, which returns unacknowledged messages, including
+// those still being processed, to the queue. The consumer stays stopped across reconnects until Resume.
+func (cm *ConsumerManager) Pause(queueName string) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if cm.paused[queueName] {
+		return
+	}
+	cm.paused[queueName] = true
+	if ch := cm.channels[queueName]; ch != nil {
+		ch.Close()
+		delete(cm.channels, queueName)
+	}
+	cm.logger.Warn("Paused consumer", zap.String("queue", queueName))
+}
+
+func (cm *ConsumerManager) Resume(queueName string) {
+	cm.mu.Lock()
+	if !cm.paused[queueName] {
+		cm.mu.Unlock()
+		return
+	}
+	cm.paused[queueName] = false
+	cm.mu.Unlock()
+
+	if err := cm.startConsumer(queueName); err != nil {
+		cm.logger.Error("Failed to resume consumer", zap.String("queue", queueName), zap.Error(err))
+		return
+	}
+	cm.logger.Info("Resumed consumer", zap.String("queue", queueName))
+}
+
+// WillRetry reports whether a delivery that failed with err is going to be redelivered.
+func (cm *ConsumerManager) WillRetry(queueName string, d amqp.Delivery, err error) bool {
+	return isDeferred(err) || !isPermanent(err) && Attempt(d) < cm.policies[queueName].MaxAttempts
+}
+
+func (cm *ConsumerManager) dispatch(queueName string, handler MessageHandler) func(amqp.Delivery) {
+	return func(d amqp.Delivery) {
+		err := handler(d)
+		if err == nil {
+			d.Ack(false)
+			return
+		}
+
+		attempt := Attempt(d)
+		if isDeferred(err) {
+			// Requeueing straight away would spin on the message for as long as the cause lasts.
+			delay := cm.policies[queueName].Delay(1)
+			if err := cm.retry(queueName, d, attempt, delay); err != nil {
+				cm.logger.Error("Failed to defer message", zap.String("queue", queueName), zap.Error(err))
+				d.Nack(false, true)
+				return
+			}
+			cm.logger.Info("Deferred message", zap.String("queue", queueName), zap.Int("attempt", attempt), zap.Duration("delay", delay), zap.Error(err))
+			d.Ack(false)
+			return
+		}
+
+		if !cm.WillRetry(queueName, d, err) {
+			cm.logger.Error("Dead-lettering message", zap.String("queue", queueName), zap.Int("attempt", attempt), zap.Error(err))
+			d.Reject(false)
+			return
+		}
+
+		delay := cm.policies[queueName].Delay(attempt)
+		if err := cm.retry(queueName, d, attempt+1, delay); err != nil {
+			cm.logger.Error("Failed to schedule retry", zap.String("queue", queueName), zap.Error(err))
+			d.Nack(false, true)
+			return
+		}
+		cm.logger.Warn("Scheduled retry", zap.String("queue", queueName), zap.Int("attempt", attempt+1), zap.Duration("delay", delay), zap.Error(err))
+		d.Ack(false)
+	}
+}
+
+func (cm *ConsumerManager) retry(queueName string, d amqp.Delivery, attempt int, delay time.Duration) error {
+	headers := amqp.Table{}
+	for key, value := range d.Headers {
+		headers[key] = value
+	}
+	headers[RetryAttemptHeader] = int32(attempt)
+
+	return cm.queue.PublishMessage(providers.DelayQueueName(queueName, delay), amqp.Publishing{
+		Headers:      headers,
+		ContentType:  d.ContentType,
+		DeliveryMode: amqp.Persistent,
+		MessageId:    d.MessageId,
+		Body:         d.Body,
+	})
+}
+
+// Attempt returns the 1-based delivery attempt of d.
+func Attempt(d amqp.Delivery) int {
+	switch value := d.Headers[RetryAttemptHeader].(type) {
+	case int32:
+		return int(value)
+	case int64:
+		return int(value)
+	case int:
+		return value
+	default:
+		return 1
+	}
+}
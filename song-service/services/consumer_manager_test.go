@@ -0,0 +1,179 @@
+package services
+
+import (
+	"errors"
+	"github.com/streadway/amqp"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+// recordingAcknowledger records how a delivery was settled.
+type recordingAcknowledger struct {
+	outcome string
+}
+
+func (a *recordingAcknowledger) Ack(uint64, bool) error {
+	a.outcome = "ack"
+	return nil
+}
+
+func (a *recordingAcknowledger) Nack(_ uint64, _ bool, requeue bool) error {
+	a.outcome = "nack"
+	if requeue {
+		a.outcome = "requeue"
+	}
+	return nil
+}
+
+func (a *recordingAcknowledger) Reject(_ uint64, requeue bool) error {
+	a.outcome = "reject"
+	if requeue {
+		a.outcome = "requeue"
+	}
+	return nil
+}
+
+func delivery(attempt int) amqp.Delivery {
+	if attempt == 0 {
+		return amqp.Delivery{}
+	}
+	return amqp.Delivery{Headers: amqp.Table{RetryAttemptHeader: int32(attempt)}}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, InitialDelay: time.Second, MaxDelay: 10 * time.Second, Multiplier: 2}
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		attempt int
+		want    time.Duration
+	}{
+		{name: "first attempt", policy: policy, attempt: 1, want: time.Second},
+		{name: "below first attempt", policy: policy, attempt: 0, want: time.Second},
+		{name: "doubles", policy: policy, attempt: 2, want: 2 * time.Second},
+		{name: "doubles again", policy: policy, attempt: 4, want: 8 * time.Second},
+		{name: "capped", policy: policy, attempt: 5, want: 10 * time.Second},
+		{name: "capped far out", policy: policy, attempt: 1000, want: 10 * time.Second},
+		{
+			name:    "constant",
+			policy:  RetryPolicy{MaxAttempts: 3, InitialDelay: time.Second, MaxDelay: time.Minute, Multiplier: 1},
+			attempt: 3,
+			want:    time.Second,
+		},
+		{
+			name:    "max below initial",
+			policy:  RetryPolicy{MaxAttempts: 3, InitialDelay: time.Second, MaxDelay: time.Millisecond, Multiplier: 2},
+			attempt: 1,
+			want:    time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Delay(tt.attempt); got != tt.want {
+				t.Errorf("Delay(%d) = %v, want %v", tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyFromEnv(t *testing.T) {
+	defaults := RetryPolicy{MaxAttempts: 5, InitialDelay: time.Second, MaxDelay: time.Minute, Multiplier: 2}
+	tests := []struct {
+		name string
+		env  map[string]string
+		want RetryPolicy
+	}{
+		{name: "defaults", want: defaults},
+		{
+			name: "overridden",
+			env:  map[string]string{"TEST_RETRY_MAX_ATTEMPTS": "3", "TEST_RETRY_INITIAL_DELAY_MS": "500", "TEST_RETRY_MULTIPLIER": "3"},
+			want: RetryPolicy{MaxAttempts: 3, InitialDelay: 500 * time.Millisecond, MaxDelay: time.Minute, Multiplier: 3},
+		},
+		{
+			name: "clamped",
+			env:  map[string]string{"TEST_RETRY_MAX_ATTEMPTS": "0", "TEST_RETRY_INITIAL_DELAY_MS": "0", "TEST_RETRY_MAX_DELAY_MS": "0", "TEST_RETRY_MULTIPLIER": "0"},
+			want: RetryPolicy{MaxAttempts: 1, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			if got := retryPolicyFromEnv("TEST_RETRY", defaults); got != tt.want {
+				t.Errorf("retryPolicyFromEnv() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttempt(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers amqp.Table
+		want    int
+	}{
+		{name: "first delivery", want: 1},
+		{name: "int32", headers: amqp.Table{RetryAttemptHeader: int32(3)}, want: 3},
+		{name: "int64", headers: amqp.Table{RetryAttemptHeader: int64(4)}, want: 4},
+		{name: "unexpected type", headers: amqp.Table{RetryAttemptHeader: "5"}, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Attempt(amqp.Delivery{Headers: tt.headers}); got != tt.want {
+				t.Errorf("Attempt() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWillRetry(t *testing.T) {
+	cm := &ConsumerManager{policies: map[string]RetryPolicy{"queue": {MaxAttempts: 3}}}
+	failure := errors.New("failure")
+	tests := []struct {
+		name    string
+		attempt int
+		err     error
+		want    bool
+	}{
+		{name: "first delivery", attempt: 0, err: failure, want: true},
+		{name: "attempts left", attempt: 2, err: failure, want: true},
+		{name: "attempts used up", attempt: 3, err: failure, want: false},
+		{name: "permanent", attempt: 0, err: permanent(failure), want: false},
+		{name: "deferred past the attempts", attempt: 3, err: deferred(failure), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cm.WillRetry("queue", delivery(tt.attempt), tt.err); got != tt.want {
+				t.Errorf("WillRetry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDispatchSettlesWithoutRetry(t *testing.T) {
+	cm := &ConsumerManager{logger: zap.NewNop(), policies: map[string]RetryPolicy{"queue": {MaxAttempts: 3}}}
+	failure := errors.New("failure")
+	tests := []struct {
+		name    string
+		attempt int
+		err     error
+		want    string
+	}{
+		{name: "success", err: nil, want: "ack"},
+		{name: "permanent failure", err: permanent(failure), want: "reject"},
+		{name: "attempts used up", attempt: 3, err: failure, want: "reject"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			acknowledger := &recordingAcknowledger{}
+			d := delivery(tt.attempt)
+			d.Acknowledger = acknowledger
+			cm.dispatch("queue", func(amqp.Delivery) error { return tt.err })(d)
+			if acknowledger.outcome != tt.want {
+				t.Errorf("delivery settled with %q, want %q", acknowledger.outcome, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SZabrodskii/music-library/utils"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"go.uber.org/zap"
+	"strings"
+)
+
+// Enricher looks up the details of a song in one source. It returns ErrSongInfoNotFound if the source
+// does not know the song and may return a partially filled detail.
+type Enricher interface {
+	Name() string
+	Enrich(ctx context.Context, group, song string) (*models.SongDetail, error)
+}
+
+type EnricherConfig struct {
+	// Providers lists the enrichers to use in priority order: api, fixtures, musicbrainz.
+	Providers       []string
+	FixturesDir     string
+	MusicBrainzDump string
+}
+
+func NewEnricherConfig() *EnricherConfig {
+	return &EnricherConfig{
+		Providers:       utils.GetEnv("ENRICHERS", []string{"api"}),
+		FixturesDir:     utils.GetEnv("ENRICHER_FIXTURES_DIR", "fixtures"),
+		MusicBrainzDump: utils.GetEnv("ENRICHER_MUSICBRAINZ_DUMP", ""),
+	}
+}
+
+// NewEnricher builds the chain of configured providers.
+func NewEnricher(logger *zap.Logger, config *EnricherConfig, songInfo *SongInfoClient) (Enricher, error) {
+	chain := &EnricherChain{logger: logger}
+	for _, name := range config.Providers {
+		switch strings.TrimSpace(name) {
+		case "api":
+			chain.enrichers = append(chain.enrichers, songInfo)
+		case "fixtures":
+			enricher, err := NewFixtureEnricher(config.FixturesDir)
+			if err != nil {
+				return nil, err
+			}
+			chain.enrichers = append(chain.enrichers, enricher)
+		case "musicbrainz":
+			enricher, err := NewMusicBrainzEnricher(config.MusicBrainzDump)
+			if err != nil {
+				return nil, err
+			}
+			chain.enrichers = append(chain.enrichers, enricher)
+		default:
+			return nil, fmt.Errorf("unknown enricher %q", name)
+		}
+	}
+	if len(chain.enrichers) == 0 {
+		return nil, errors.New("no enrichers configured")
+	}
+	return chain, nil
+}
+
+// EnricherChain asks its enrichers in priority order and merges their answers field by field: a field is
+// taken from the first enricher that knows it. It stops as soon as every field is filled.
+type EnricherChain struct {
+	logger    *zap.Logger
+	enrichers []Enricher
+}
+
+func (c *EnricherChain) Name() string {
+	names := make([]string, 0, len(c.enrichers))
+	for _, enricher := range c.enrichers {
+		names = append(names, enricher.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+// Enrich returns ErrSongInfoNotFound if no enricher knows the song. If some enrichers fail, the merged
+// answer of the others is returned even if fields are left empty; the scheduled refresh fills them later.
+// Only when every enricher that could have known the song failed is the first failure returned.
+func (c *EnricherChain) Enrich(ctx context.Context, group, song string) (*models.SongDetail, error) {
+	result := &models.SongDetail{}
+	found := false
+	var failure error
+	for _, enricher := range c.enrichers {
+		detail, err := enricher.Enrich(ctx, group, song)
+		if errors.Is(err, ErrSongInfoNotFound) {
+			continue
+		}
+		if err != nil {
+			c.logger.Warn("Enricher failed", zap.String("enricher", enricher.Name()), zap.Error(err))
+			if failure == nil {
+				failure = err
+			}
+			continue
+		}
+		found = true
+		mergeSongDetail(result, detail)
+		if isCompleteSongDetail(result) {
+			return result, nil
+		}
+	}
+
+	if found {
+		return result, nil
+	}
+	if failure != nil {
+		return nil, failure
+	}
+	return nil, ErrSongInfoNotFound
+}
+
+func mergeSongDetail(dst, src *models.SongDetail) {
+	if dst.ReleaseDate == "" {
+		dst.ReleaseDate = src.ReleaseDate
+	}
+	if dst.Text == "" {
+		dst.Text = src.Text
+	}
+	if dst.Link == "" {
+		dst.Link = src.Link
+	}
+}
+
+func isCompleteSongDetail(detail *models.SongDetail) bool {
+	return detail.ReleaseDate != "" && detail.Text != "" && detail.Link != ""
+}
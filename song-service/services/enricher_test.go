@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type stubEnricher struct {
+	name   string
+	detail *models.SongDetail
+	err    error
+	calls  int
+}
+
+func (e *stubEnricher) Name() string {
+	return e.name
+}
+
+func (e *stubEnricher) Enrich(context.Context, string, string) (*models.SongDetail, error) {
+	e.calls++
+	return e.detail, e.err
+}
+
+func TestEnricherChain(t *testing.T) {
+	complete := &models.SongDetail{ReleaseDate: "2006-07-16", Text: "text", Link: "https://example.com/a"}
+	errUnavailable := errors.New("unavailable")
+
+	tests := []struct {
+		name      string
+		enrichers []*stubEnricher
+		want      *models.SongDetail
+		wantErr   error
+		// wantCalls is how often each enricher is asked.
+		wantCalls []int
+	}{
+		{
+			name: "stops at a complete answer",
+			enrichers: []*stubEnricher{
+				{name: "api", detail: complete},
+				{name: "fixtures", detail: &models.SongDetail{Text: "other"}},
+			},
+			want:      complete,
+			wantCalls: []int{1, 0},
+		},
+		{
+			name: "merges fields in priority order",
+			enrichers: []*stubEnricher{
+				{name: "api", detail: &models.SongDetail{Text: "api text"}},
+				{name: "fixtures", err: ErrSongInfoNotFound},
+				{name: "musicbrainz", detail: &models.SongDetail{ReleaseDate: "2006", Text: "mb text"}},
+			},
+			want:      &models.SongDetail{ReleaseDate: "2006", Text: "api text"},
+			wantCalls: []int{1, 1, 1},
+		},
+		{
+			name: "falls back while an enricher fails",
+			enrichers: []*stubEnricher{
+				{name: "api", err: ErrCircuitOpen},
+				{name: "fixtures", detail: &models.SongDetail{Link: "https://example.com/b"}},
+			},
+			want:      &models.SongDetail{Link: "https://example.com/b"},
+			wantCalls: []int{1, 1},
+		},
+		{
+			name: "nobody knows the song",
+			enrichers: []*stubEnricher{
+				{name: "api", err: ErrSongInfoNotFound},
+				{name: "fixtures", err: ErrSongInfoNotFound},
+			},
+			wantErr:   ErrSongInfoNotFound,
+			wantCalls: []int{1, 1},
+		},
+		{
+			name: "failure wins over not found",
+			enrichers: []*stubEnricher{
+				{name: "api", err: ErrSongInfoNotFound},
+				{name: "fixtures", err: errUnavailable},
+			},
+			wantErr:   errUnavailable,
+			wantCalls: []int{1, 1},
+		},
+		{
+			name: "first failure is returned",
+			enrichers: []*stubEnricher{
+				{name: "api", err: ErrCircuitOpen},
+				{name: "fixtures", err: errUnavailable},
+			},
+			wantErr:   ErrCircuitOpen,
+			wantCalls: []int{1, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := &EnricherChain{logger: zap.NewNop()}
+			for _, enricher := range tt.enrichers {
+				chain.enrichers = append(chain.enrichers, enricher)
+			}
+
+			got, err := chain.Enrich(context.Background(), "Muse", "Uprising")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Enrich() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Enrich() = %+v, want %+v", got, tt.want)
+			}
+			for i, enricher := range tt.enrichers {
+				if enricher.calls != tt.wantCalls[i] {
+					t.Errorf("enricher %s asked %d times, want %d", enricher.name, enricher.calls, tt.wantCalls[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFixtureEnricher(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"list.yaml":   "- group: Muse\n  song: Uprising\n  releaseDate: 07.09.2009\n- group: Muse\n  song: Hysteria\n  text: It's bugging me\n",
+		"single.json": `{"group": "AC/DC", "song": "T.N.T.", "link": "https://example.com/tnt"}`,
+		"notes.txt":   "not a fixture",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	enricher, err := NewFixtureEnricher(dir)
+	if err != nil {
+		t.Fatalf("NewFixtureEnricher() error = %v", err)
+	}
+
+	tests := []struct {
+		group   string
+		song    string
+		want    *models.SongDetail
+		wantErr error
+	}{
+		{group: "muse", song: "uprising", want: &models.SongDetail{ReleaseDate: "07.09.2009"}},
+		{group: "Muse", song: "Hysteria", want: &models.SongDetail{Text: "It's bugging me"}},
+		{group: "ac dc", song: "tnt", wantErr: ErrSongInfoNotFound},
+		{group: "AC/DC", song: "T.N.T.", want: &models.SongDetail{Link: "https://example.com/tnt"}},
+		{group: "Muse", song: "Starlight", wantErr: ErrSongInfoNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.group+" - "+tt.song, func(t *testing.T) {
+			got, err := enricher.Enrich(context.Background(), tt.group, tt.song)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Enrich() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Enrich() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SongFixture is a song as stored in a fixture file. A file holds one fixture or a list of them.
+type SongFixture struct {
+	Group       string `json:"group" yaml:"group"`
+	Song        string `json:"song" yaml:"song"`
+	ReleaseDate string `json:"releaseDate" yaml:"releaseDate"`
+	Text        string `json:"text" yaml:"text"`
+	Link        string `json:"link" yaml:"link"`
+}
+
+// FixtureEnricher serves song details from the .json, .yaml and .yml files of a directory, loaded at startup.
+type FixtureEnricher struct {
+	songs map[string]*models.SongDetail
+}
+
+func NewFixtureEnricher(dir string) (*FixtureEnricher, error) {
+	fixtures, err := LoadSongFixtures(dir)
+	if err != nil {
+		return nil, err
+	}
+	songs := make(map[string]*models.SongDetail, len(fixtures))
+	for _, fixture := range fixtures {
+		songs[models.NormalizedKey(fixture.Group, fixture.Song)] = &models.SongDetail{
+			ReleaseDate: fixture.ReleaseDate,
+			Text:        fixture.Text,
+			Link:        fixture.Link,
+		}
+	}
+	return &FixtureEnricher{songs: songs}, nil
+}
+
+func (e *FixtureEnricher) Name() string {
+	return "fixtures"
+}
+
+func (e *FixtureEnricher) Enrich(_ context.Context, group, song string) (*models.SongDetail, error) {
+	detail, ok := e.songs[models.NormalizedKey(group, song)]
+	if !ok {
+		return nil, ErrSongInfoNotFound
+	}
+	copied := *detail
+	return &copied, nil
+}
+
+// LoadSongFixtures reads every fixture file in dir.
+func LoadSongFixtures(dir string) ([]*SongFixture, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+
+	var fixtures []*SongFixture
+	for _, entry := range entries {
+		var unmarshal func([]byte, interface{}) error
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".json":
+			unmarshal = json.Unmarshal
+		case ".yaml", ".yml":
+			unmarshal = yaml.Unmarshal
+		default:
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture %s: %w", entry.Name(), err)
+		}
+		var list []*SongFixture
+		if err := unmarshal(data, &list); err != nil {
+			var single SongFixture
+			if err := unmarshal(data, &single); err != nil {
+				return nil, fmt.Errorf("failed to parse fixture %s: %w", entry.Name(), err)
+			}
+			list = []*SongFixture{&single}
+		}
+		fixtures = append(fixtures, list...)
+	}
+	return fixtures, nil
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrGenreNotFound = errors.New("genre not found")
+	ErrGenreExists   = errors.New("genre already exists")
+	ErrInvalidGenre  = errors.New("invalid genre")
+)
+
+// genreFacetsQuery counts the songs of the subquery in each genre, including the songs of its descendants.
+const genreFacetsQuery = `WITH RECURSIVE lineage AS (
+    SELECT id AS genre_id, id AS ancestor_id FROM genres
+    UNION ALL
+    SELECT lineage.genre_id, genres.parent_id
+    FROM lineage
+             JOIN genres ON genres.id = lineage.ancestor_id
+    WHERE genres.parent_id IS NOT NULL
+)
+SELECT genres.slug, genres.name, COUNT(DISTINCT song_genres.song_id) AS count
+FROM song_genres
+         JOIN lineage ON lineage.genre_id = song_genres.genre_id
+         JOIN genres ON genres.id = lineage.ancestor_id
+WHERE song_genres.song_id IN (?)
+GROUP BY genres.id
+ORDER BY count DESC, genres.slug`
+
+type GenreService struct {
+	logger *zap.Logger
+	db     *gorm.DB
+}
+
+func NewGenreService(logger *zap.Logger, db *gorm.DB) *GenreService {
+	return &GenreService{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// GetGenres returns the genre taxonomy as a tree, each level ordered by name.
+func (s *GenreService) GetGenres() ([]*models.Genre, error) {
+	var genres []*models.Genre
+	if err := s.db.Order("name").Order("id").Find(&genres).Error; err != nil {
+		return nil, err
+	}
+	facets, err := s.Facets(s.db.Model(&models.Song{}).Select("songs.id"))
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(facets))
+	for _, facet := range facets {
+		counts[facet.Slug] = facet.Count
+	}
+
+	byID := make(map[uint]*models.Genre, len(genres))
+	for _, genre := range genres {
+		genre.SongCount = counts[genre.Slug]
+		byID[genre.ID] = genre
+	}
+	roots := make([]*models.Genre, 0)
+	for _, genre := range genres {
+		if parent, ok := byID[derefID(genre.ParentID)]; ok {
+			parent.Children = append(parent.Children, genre)
+		} else {
+			roots = append(roots, genre)
+		}
+	}
+	return roots, nil
+}
+
+// CreateGenre adds a genre below the genre ParentID, or at the top level.
+func (s *GenreService) CreateGenre(genre *models.Genre) (*models.Genre, error) {
+	created := &models.Genre{Name: genre.Name, Slug: models.Slug(genre.Name), ParentID: genre.ParentID}
+	if created.Slug == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidGenre)
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if created.ParentID != nil {
+			if err := tx.Where("id = ?", *created.ParentID).First(&models.Genre{}).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return fmt.Errorf("%w: parent %d does not exist", ErrInvalidGenre, *created.ParentID)
+				}
+				return fmt.Errorf("failed to find parent genre: %w", err)
+			}
+		}
+		result := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "slug"}}, DoNothing: true}).Create(created)
+		if result.Error != nil {
+			return fmt.Errorf("failed to create genre: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrGenreExists
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// AttachGenre puts the song songID in the genre identified by name. Attaching it twice is a no-op.
+func (s *GenreService) AttachGenre(songID, name string) error {
+	if err := findSong(s.db, songID); err != nil {
+		return err
+	}
+	genre, err := s.findGenre(name)
+	if err != nil {
+		return err
+	}
+	err = s.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.SongGenre{SongID: parseSongID(songID), GenreID: genre.ID}).Error
+	if err != nil {
+		return fmt.Errorf("failed to attach genre: %w", err)
+	}
+	return nil
+}
+
+// DetachGenre removes the song songID from the genre identified by name, if it is in it.
+func (s *GenreService) DetachGenre(songID, name string) error {
+	if err := findSong(s.db, songID); err != nil {
+		return err
+	}
+	err := s.db.Where("song_id = ? AND genre_id IN (?)", songID, s.db.Model(&models.Genre{}).Select("id").Where("slug = ?", models.Slug(name))).
+		Delete(&models.SongGenre{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to detach genre: %w", err)
+	}
+	return nil
+}
+
+// LoadGenres fills the Genres of songs with the genres they were attached to, ordered by name.
+func (s *GenreService) LoadGenres(tx *gorm.DB, songs []*models.Song) error {
+	if len(songs) == 0 {
+		return nil
+	}
+	var rows []struct {
+		SongID uint
+		Slug   string
+		Name   string
+	}
+	err := tx.Table("song_genres").
+		Select("song_genres.song_id, genres.slug, genres.name").
+		Joins("JOIN genres ON genres.id = song_genres.genre_id").
+		Where("song_genres.song_id IN ?", songIDs(songs)).
+		Order("genres.name").
+		Scan(&rows).Error
+	if err != nil {
+		return fmt.Errorf("failed to load genres: %w", err)
+	}
+
+	bySong := make(map[uint][]*models.Label, len(songs))
+	for _, row := range rows {
+		bySong[row.SongID] = append(bySong[row.SongID], &models.Label{Slug: row.Slug, Name: row.Name})
+	}
+	for _, song := range songs {
+		song.Genres = bySong[song.ID]
+	}
+	return nil
+}
+
+// Facets counts the songs selected by the subquery songs, which must select song IDs, per genre.
+func (s *GenreService) Facets(songs *gorm.DB) ([]*models.Facet, error) {
+	facets := make([]*models.Facet, 0)
+	if err := s.db.Raw(genreFacetsQuery, songs).Scan(&facets).Error; err != nil {
+		return nil, fmt.Errorf("failed to count genres: %w", err)
+	}
+	return facets, nil
+}
+
+func (s *GenreService) findGenre(name string) (*models.Genre, error) {
+	var genre models.Genre
+	if err := s.db.Where("slug = ?", models.Slug(name)).First(&genre).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGenreNotFound
+		}
+		return nil, fmt.Errorf("failed to find genre: %w", err)
+	}
+	return &genre, nil
+}
+
+func derefID(id *uint) uint {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"github.com/SZabrodskii/music-library/song-service/migrations"
+	"github.com/SZabrodskii/music-library/utils/providers"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testDB connects to the database in TEST_DATABASE_URL, applies the migrations and empties every table.
+// Tests using it are skipped when the variable is not set.
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to connect to the test database: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	// The migrations are read relative to the song service root.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(".."); err != nil {
+		t.Fatal(err)
+	}
+	err = migrations.ApplyMigrations(db)
+	if chdirErr := os.Chdir(wd); chdirErr != nil {
+		t.Fatal(chdirErr)
+	}
+	if err != nil {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+
+	var tables []string
+	err = db.Raw("SELECT tablename FROM pg_tables WHERE schemaname = current_schema() AND tablename <> 'migrations'").
+		Scan(&tables).Error
+	if err != nil {
+		t.Fatalf("failed to list tables: %v", err)
+	}
+	if err := db.Exec("TRUNCATE " + strings.Join(tables, ", ") + " RESTART IDENTITY CASCADE").Error; err != nil {
+		t.Fatalf("failed to empty tables: %v", err)
+	}
+	return db
+}
+
+// testQueue connects to the broker in TEST_RABBITMQ_URL and declares a uniquely named queue that is
+// deleted after the test. Tests using it are skipped when the variable is not set.
+func testQueue(t *testing.T) (*providers.RabbitMQProvider, string) {
+	t.Helper()
+	url := os.Getenv("TEST_RABBITMQ_URL")
+	if url == "" {
+		t.Skip("TEST_RABBITMQ_URL is not set")
+	}
+	queue, err := providers.NewRabbitMQProvider(zap.NewNop(), &providers.RabbitMQProviderConfig{
+		URL:               url,
+		ReconnectDelay:    time.Second,
+		ReconnectMaxDelay: time.Second,
+		ConfirmTimeout:    5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to the test broker: %v", err)
+	}
+	t.Cleanup(func() { queue.Close() })
+
+	queueName := "test_" + uuid.NewString()
+	if _, err := queue.GetChannel().QueueDeclare(queueName, false, false, false, false, nil); err != nil {
+		t.Fatalf("failed to declare %s: %v", queueName, err)
+	}
+	t.Cleanup(func() { queue.GetChannel().QueueDelete(queueName, false, false, false) })
+	return queue, queueName
+}
+
+// testCache returns a cache backed by an in-memory Redis server.
+func testCache(t *testing.T) *providers.CacheProvider {
+	t.Helper()
+	redis, err := providers.NewRedisProvider(&providers.RedisProviderConfig{Addr: miniredis.RunT(t).Addr()})
+	if err != nil {
+		t.Fatalf("failed to connect to the test redis: %v", err)
+	}
+	return providers.NewCacheProvider(zap.NewNop(), redis)
+}
+
+// newTestSongService returns a song service on the test database that enriches songs with enricher.
+func newTestSongService(t *testing.T, enricher Enricher) (*SongService, *gorm.DB) {
+	t.Helper()
+	db := testDB(t)
+	return &SongService{
+		logger:    zap.NewNop(),
+		db:        db,
+		cache:     testCache(t),
+		jobs:      NewJobService(zap.NewNop(), db),
+		outbox:    NewOutboxService(zap.NewNop(), db, nil, &OutboxServiceConfig{PollInterval: time.Hour, BatchSize: 10}),
+		artists:   NewArtistService(zap.NewNop(), db),
+		revisions: NewRevisionService(zap.NewNop(), db),
+		enricher:  enricher,
+		config: &SongServiceConfig{
+			RefreshStaleAfter:   24 * time.Hour,
+			RefreshMissingAfter: time.Hour,
+			RefreshBatchSize:    10,
+		},
+	}, db
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"errors"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var ErrJobNotFound = errors.New("job not found")
+
+type JobService struct {
+	logger *zap.Logger
+	db     *gorm.DB
+}
+
+func NewJobService(logger *zap.Logger, db *gorm.DB) *JobService {
+	return &JobService{
+		logger: logger,
+		db:     db,
+	}
+}
+
+func (s *JobService) CreateJob(jobType string) (*models.Job, error) {
+	return s.CreateJobTx(s.db, jobType)
+}
+
+// CreateJobTx creates a pending job using tx, e.g. together with the outbox message that starts it.
+func (s *JobService) CreateJobTx(tx *gorm.DB, jobType string) (*models.Job, error) {
+	job := &models.Job{
+		ID:     uuid.NewString(),
+		Type:   jobType,
+		Status: models.JobStatusPending,
+	}
+	if err := tx.Create(job).Error; err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *JobService) GetJob(jobID string) (*models.Job, error) {
+	if _, err := uuid.Parse(jobID); err != nil {
+		return nil, ErrJobNotFound
+	}
+
+	var job models.Job
+	if err := s.db.Where("id = ?", jobID).First(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (s *JobService) MarkRunning(jobID string) {
+	s.update(jobID, map[string]interface{}{"status": models.JobStatusRunning})
+}
+
+// MarkRetrying puts the job back to pending after a transient failure, keeping the reason visible.
+func (s *JobService) MarkRetrying(jobID string, reason error) {
+	s.update(jobID, map[string]interface{}{"status": models.JobStatusPending, "error": reason.Error()})
+}
+
+func (s *JobService) MarkSucceeded(jobID string, songID uint) {
+	s.update(jobID, map[string]interface{}{"status": models.JobStatusSucceeded, "error": "", "song_id": songID})
+}
+
+func (s *JobService) MarkFailed(jobID string, reason error) {
+	s.update(jobID, map[string]interface{}{"status": models.JobStatusFailed, "error": reason.Error()})
+}
+
+func (s *JobService) update(jobID string, fields map[string]interface{}) {
+	if jobID == "" {
+		return
+	}
+	if err := s.db.Model(&models.Job{}).Where("id = ?", jobID).Updates(fields).Error; err != nil {
+		s.logger.Error("Failed to update job", zap.String("jobId", jobID), zap.Any("fields", fields), zap.Error(err))
+	}
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"os"
+	"strings"
+)
+
+type musicBrainzRecording struct {
+	ID               string `json:"id"`
+	Title            string `json:"title"`
+	FirstReleaseDate string `json:"first-release-date"`
+	ArtistCredit     []struct {
+		Name       string `json:"name"`
+		JoinPhrase string `json:"joinphrase"`
+	} `json:"artist-credit"`
+}
+
+// MusicBrainzEnricher serves release dates and links from a MusicBrainz recording dump in JSON Lines format
+// (one recording per line, as in the JSON data dumps). It knows no lyrics. When several recordings share a
+// title and artist, the earliest release date wins.
+type MusicBrainzEnricher struct {
+	songs map[string]*models.SongDetail
+}
+
+func NewMusicBrainzEnricher(path string) (*MusicBrainzEnricher, error) {
+	if path == "" {
+		return nil, errors.New("ENRICHER_MUSICBRAINZ_DUMP is not set")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open musicbrainz dump: %w", err)
+	}
+	defer file.Close()
+
+	songs := make(map[string]*models.SongDetail)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 1024*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var recording musicBrainzRecording
+		if err := json.Unmarshal(scanner.Bytes(), &recording); err != nil {
+			return nil, fmt.Errorf("failed to parse musicbrainz dump line %d: %w", line, err)
+		}
+
+		var artist strings.Builder
+		for _, credit := range recording.ArtistCredit {
+			artist.WriteString(credit.Name + credit.JoinPhrase)
+		}
+		key := models.NormalizedKey(artist.String(), recording.Title)
+		if existing, ok := songs[key]; ok && (recording.FirstReleaseDate == "" || existing.ReleaseDate != "" && existing.ReleaseDate <= recording.FirstReleaseDate) {
+			continue
+		}
+		songs[key] = &models.SongDetail{
+			ReleaseDate: recording.FirstReleaseDate,
+			Link:        "https://musicbrainz.org/recording/" + recording.ID,
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read musicbrainz dump: %w", err)
+	}
+	return &MusicBrainzEnricher{songs: songs}, nil
+}
+
+func (e *MusicBrainzEnricher) Name() string {
+	return "musicbrainz"
+}
+
+func (e *MusicBrainzEnricher) Enrich(_ context.Context, group, song string) (*models.SongDetail, error) {
+	detail, ok := e.songs[models.NormalizedKey(group, song)]
+	if !ok {
+		return nil, ErrSongInfoNotFound
+	}
+	copied := *detail
+	return &copied, nil
+}
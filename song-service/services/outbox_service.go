@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/SZabrodskii/music-library/utils"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"github.com/SZabrodskii/music-library/utils/providers"
+	"github.com/google/uuid"
+	"github.com/streadway/amqp"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type OutboxServiceConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	// ProcessedRetention is how long the IDs of consumed messages are kept to detect redeliveries.
+	ProcessedRetention time.Duration
+}
+
+func NewOutboxServiceConfig() *OutboxServiceConfig {
+	return &OutboxServiceConfig{
+		PollInterval:       time.Duration(utils.GetEnv("OUTBOX_POLL_INTERVAL_MS", 1000)) * time.Millisecond,
+		BatchSize:          utils.GetEnv("OUTBOX_BATCH_SIZE", 100),
+		ProcessedRetention: time.Duration(utils.GetEnv("PROCESSED_MESSAGES_RETENTION_HOURS", 168)) * time.Hour,
+	}
+}
+
+// OutboxService stores queue messages in Postgres alongside the change that produced them and relays
+// them to RabbitMQ, so a message is never lost between committing a request and publishing it.
+type OutboxService struct {
+	logger *zap.Logger
+	db     *gorm.DB
+	queue  *providers.RabbitMQProvider
+	config *OutboxServiceConfig
+	notify chan struct{}
+}
+
+func NewOutboxService(logger *zap.Logger, db *gorm.DB, queue *providers.RabbitMQProvider, config *OutboxServiceConfig) *OutboxService {
+	return &OutboxService{
+		logger: logger,
+		db:     db,
+		queue:  queue,
+		config: config,
+		notify: make(chan struct{}, 1),
+	}
+}
+
+// Add stores payload for queueName using tx, so the message is committed or rolled back together with it.
+// Every message gets a unique ID, published as the AMQP message ID, which consumers use to skip redeliveries.
+func (s *OutboxService) Add(tx *gorm.DB, queueName string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return tx.Create(&models.OutboxMessage{MessageID: uuid.NewString(), Queue: queueName, Payload: body}).Error
+}
+
+// Notify wakes the relay up without waiting for the next poll.
+func (s *OutboxService) Notify() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Run relays pending messages until ctx is cancelled.
+func (s *OutboxService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.notify:
+		}
+
+		for {
+			relayed, err := s.relay()
+			if err != nil {
+				s.logger.Error("Failed to relay outbox messages", zap.Error(err))
+				break
+			}
+			if relayed < s.config.BatchSize {
+				break
+			}
+		}
+	}
+}
+
+// relay publishes a batch of pending messages in order and marks them sent. Rows are locked with
+// SKIP LOCKED so several instances can relay concurrently. It stops at the first failed publish.
+func (s *OutboxService) relay() (int, error) {
+	relayed := 0
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var messages []*models.OutboxMessage
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("sent_at IS NULL").
+			Order("id").
+			Limit(s.config.BatchSize).
+			Find(&messages).Error
+		if err != nil {
+			return err
+		}
+
+		for _, message := range messages {
+			err := s.queue.PublishMessage(message.Queue, amqp.Publishing{
+				ContentType:  "application/json",
+				DeliveryMode: amqp.Persistent,
+				MessageId:    message.MessageID,
+				Body:         message.Payload,
+			})
+			if err != nil {
+				return tx.Model(message).Updates(map[string]interface{}{
+					"attempts":   gorm.Expr("attempts + 1"),
+					"last_error": err.Error(),
+				}).Error
+			}
+			if err := tx.Model(message).Update("sent_at", time.Now()).Error; err != nil {
+				return err
+			}
+			relayed++
+		}
+		return nil
+	})
+	return relayed, err
+}
+
+// RunPruning deletes processed message IDs and sent outbox messages older than ProcessedRetention every
+// hour until ctx is cancelled. A message is only redelivered shortly after it was consumed, so older rows
+// are no longer needed.
+func (s *OutboxService) RunPruning(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		s.pruneProcessed()
+		s.pruneSent()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *OutboxService) pruneProcessed() {
+	result := s.db.Where("processed_at < ?", time.Now().Add(-s.config.ProcessedRetention)).Delete(&models.ProcessedMessage{})
+	if result.Error != nil {
+		s.logger.Error("Failed to prune processed messages", zap.Error(result.Error))
+		return
+	}
+	if result.RowsAffected > 0 {
+		s.logger.Info("Pruned processed messages", zap.Int64("count", result.RowsAffected))
+	}
+}
+
+func (s *OutboxService) pruneSent() {
+	result := s.db.Where("sent_at < ?", time.Now().Add(-s.config.ProcessedRetention)).Delete(&models.OutboxMessage{})
+	if result.Error != nil {
+		s.logger.Error("Failed to prune sent outbox messages", zap.Error(result.Error))
+		return
+	}
+	if result.RowsAffected > 0 {
+		s.logger.Info("Pruned sent outbox messages", zap.Int64("count", result.RowsAffected))
+	}
+}
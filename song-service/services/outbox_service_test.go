@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOutboxRelaysInOrder(t *testing.T) {
+	db := testDB(t)
+	queue, queueName := testQueue(t)
+	outbox := NewOutboxService(zap.NewNop(), db, queue, &OutboxServiceConfig{PollInterval: time.Hour, BatchSize: 2})
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, id := range []int{1, 2, 3} {
+			if err := outbox.Add(tx, queueName, map[string]int{"id": id}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	for _, want := range []int{2, 1, 0} {
+		relayed, err := outbox.relay()
+		if err != nil {
+			t.Fatalf("relay() error = %v", err)
+		}
+		if relayed != want {
+			t.Errorf("relay() = %d, want %d", relayed, want)
+		}
+	}
+
+	var pending int64
+	db.Model(&models.OutboxMessage{}).Where("sent_at IS NULL").Count(&pending)
+	if pending != 0 {
+		t.Errorf("%d messages left unsent", pending)
+	}
+
+	for _, want := range []int{1, 2, 3} {
+		d, ok, err := queue.GetChannel().Get(queueName, true)
+		if err != nil || !ok {
+			t.Fatalf("Get() = %v, %v, want a message", ok, err)
+		}
+		var payload map[string]int
+		if err := json.Unmarshal(d.Body, &payload); err != nil {
+			t.Fatalf("received invalid JSON %s: %v", d.Body, err)
+		}
+		if payload["id"] != want {
+			t.Errorf("received %s, want id %d", d.Body, want)
+		}
+	}
+}
+
+func TestOutboxDropsRolledBackMessages(t *testing.T) {
+	db := testDB(t)
+	outbox := NewOutboxService(zap.NewNop(), db, nil, &OutboxServiceConfig{PollInterval: time.Hour, BatchSize: 10})
+
+	rollback := errors.New("rollback")
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := outbox.Add(tx, "queue", map[string]int{"id": 1}); err != nil {
+			return err
+		}
+		return rollback
+	})
+	if !errors.Is(err, rollback) {
+		t.Fatalf("Transaction() error = %v, want %v", err, rollback)
+	}
+
+	var count int64
+	db.Model(&models.OutboxMessage{}).Count(&count)
+	if count != 0 {
+		t.Errorf("%d messages stored after rollback, want 0", count)
+	}
+}
+
+func TestOutboxRunRelaysOnNotify(t *testing.T) {
+	db := testDB(t)
+	queue, queueName := testQueue(t)
+	outbox := NewOutboxService(zap.NewNop(), db, queue, &OutboxServiceConfig{PollInterval: time.Hour, BatchSize: 10})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go outbox.Run(ctx)
+
+	if err := outbox.Add(db, queueName, map[string]int{"id": 1}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	outbox.Notify()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, ok, err := queue.GetChannel().Get(queueName, true); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		} else if ok {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatal("message was not relayed after Notify")
+}
+
+func TestOutboxPrunesOldRows(t *testing.T) {
+	db := testDB(t)
+	outbox := NewOutboxService(zap.NewNop(), db, nil, &OutboxServiceConfig{ProcessedRetention: time.Hour})
+	old, recent := time.Now().Add(-2*time.Hour), time.Now()
+
+	db.Create([]*models.OutboxMessage{
+		{MessageID: "old", Queue: "queue", Payload: json.RawMessage(`{}`), SentAt: &old},
+		{MessageID: "recent", Queue: "queue", Payload: json.RawMessage(`{}`), SentAt: &recent},
+		{MessageID: "pending", Queue: "queue", Payload: json.RawMessage(`{}`)},
+	})
+	db.Create([]*models.ProcessedMessage{
+		{MessageID: "old", Queue: "queue", ProcessedAt: old},
+		{MessageID: "recent", Queue: "queue", ProcessedAt: recent},
+	})
+
+	outbox.pruneProcessed()
+	outbox.pruneSent()
+
+	var messages, processed []string
+	db.Model(&models.OutboxMessage{}).Order("message_id").Pluck("message_id", &messages)
+	db.Model(&models.ProcessedMessage{}).Order("message_id").Pluck("message_id", &processed)
+	if want := []string{"pending", "recent"}; !reflect.DeepEqual(messages, want) {
+		t.Errorf("outbox messages = %v, want %v", messages, want)
+	}
+	if want := []string{"recent"}; !reflect.DeepEqual(processed, want) {
+		t.Errorf("processed messages = %v, want %v", processed, want)
+	}
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"github.com/SZabrodskii/music-library/utils/pagination"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"net/url"
+)
+
+var ErrRevisionNotFound = errors.New("revision not found")
+
+const (
+	// SystemActor makes the changes nobody asked for, such as scheduled refreshes.
+	SystemActor    = "system"
+	anonymousActor = "anonymous"
+)
+
+// Origin tells who asked for a change and in which trace; queue messages carry it to the revision.
+type Origin struct {
+	Actor   string `json:"actor,omitempty"`
+	TraceID string `json:"traceId,omitempty"`
+}
+
+type RevisionService struct {
+	logger *zap.Logger
+	db     *gorm.DB
+}
+
+func NewRevisionService(logger *zap.Logger, db *gorm.DB) *RevisionService {
+	return &RevisionService{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// Record stores the current state of the song songID as its next revision, unless nothing changed since
+// the last one. It must run in the transaction of the change.
+func (s *RevisionService) Record(tx *gorm.DB, songID uint, action string, origin Origin) error {
+	snapshot, err := s.snapshot(tx, songID)
+	if err != nil {
+		return err
+	}
+
+	var previous models.SongRevision
+	if err := tx.Where("song_id = ?", songID).Order("revision DESC").Limit(1).Find(&previous).Error; err != nil {
+		return fmt.Errorf("failed to find revision: %w", err)
+	}
+	var before *models.SongSnapshot
+	if previous.ID != 0 {
+		before = &models.SongSnapshot{}
+		if err := json.Unmarshal(previous.Snapshot, before); err != nil {
+			return fmt.Errorf("failed to read revision %d: %w", previous.Revision, err)
+		}
+	}
+	diff := models.DiffSnapshots(before, snapshot)
+	if before != nil && len(diff) == 0 {
+		return nil
+	}
+
+	revision := &models.SongRevision{
+		SongID:   songID,
+		Revision: previous.Revision + 1,
+		Action:   action,
+		Actor:    coalesce(origin.Actor, anonymousActor),
+		TraceID:  origin.TraceID,
+	}
+	if revision.Snapshot, err = json.Marshal(snapshot); err != nil {
+		return err
+	}
+	if revision.Diff, err = json.Marshal(diff); err != nil {
+		return err
+	}
+	if err := tx.Create(revision).Error; err != nil {
+		return fmt.Errorf("failed to create revision: %w", err)
+	}
+	return nil
+}
+
+type GetHistoryRequest struct {
+	SongID   string `json:"songId"`
+	Page     string `json:"page"`
+	PageSize string `json:"pageSize"`
+}
+
+// GetHistory lists the revisions of a song, deleted or not, newest first.
+func (s *RevisionService) GetHistory(req *GetHistoryRequest) ([]*models.SongRevision, *pagination.Pagination, error) {
+	if err := s.db.Unscoped().Where("id = ?", req.SongID).First(&models.Song{}).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrSongNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to find song: %w", err)
+	}
+
+	revisions := make([]*models.SongRevision, 0)
+	query := s.db.Model(&models.SongRevision{}).Where("song_id = ?", req.SongID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, nil, err
+	}
+
+	page, pageSize := pagination.Normalize(req.Page, req.PageSize)
+	if err := query.Order("revision DESC").Offset(pagination.Offset(page, pageSize)).Limit(pageSize).Find(&revisions).Error; err != nil {
+		return nil, nil, err
+	}
+
+	meta := pagination.New(total, page, pageSize, url.Values{})
+	return revisions, &meta, nil
+}
+
+// FindSnapshot returns the state of the song songID as of revision.
+func (s *RevisionService) FindSnapshot(tx *gorm.DB, songID string, revision string) (*models.SongSnapshot, error) {
+	var found models.SongRevision
+	if err := tx.Where("song_id = ? AND revision = ?", songID, revision).First(&found).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, fmt.Errorf("failed to find revision: %w", err)
+	}
+	var snapshot models.SongSnapshot
+	if err := json.Unmarshal(found.Snapshot, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to read revision %d: %w", found.Revision, err)
+	}
+	return &snapshot, nil
+}
+
+func (s *RevisionService) snapshot(tx *gorm.DB, songID uint) (*models.SongSnapshot, error) {
+	var song models.Song
+	if err := tx.Unscoped().Where("id = ?", songID).First(&song).Error; err != nil {
+		return nil, fmt.Errorf("failed to find song: %w", err)
+	}
+	var verses []string
+	if err := tx.Model(&models.Verse{}).Where("song_id = ?", songID).Order("id").Pluck("text", &verses).Error; err != nil {
+		return nil, fmt.Errorf("failed to find verses: %w", err)
+	}
+	if verses == nil {
+		verses = []string{}
+	}
+	return &models.SongSnapshot{
+		GroupName:   song.GroupName,
+		SongName:    song.SongName,
+		ReleaseDate: song.ReleaseDate,
+		Link:        song.Link,
+		Verses:      verses,
+		Deleted:     song.DeletedAt.Valid,
+	}, nil
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SZabrodskii/music-library/utils"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"go.uber.org/zap"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+var (
+	// ErrSongInfoNotFound means the API does not know the song; retrying will not help.
+	ErrSongInfoNotFound = errors.New("song info not found")
+	// ErrSongInfoRejected means the API refused the request with another 4xx status or sent an unreadable response.
+	ErrSongInfoRejected = errors.New("song info request rejected")
+	// ErrSongInfoUnavailable means the API failed with 5xx or network errors on every attempt.
+	ErrSongInfoUnavailable = errors.New("song info API unavailable")
+)
+
+type SongInfoClientConfig struct {
+	Host             string
+	Timeout          time.Duration
+	MaxRetries       int
+	RetryDelay       time.Duration
+	FailureThreshold int
+	OpenDuration     time.Duration
+}
+
+func NewSongInfoClientConfig() *SongInfoClientConfig {
+	return &SongInfoClientConfig{
+		Host:             utils.GetEnv("SONG_INFO_API_HOST", "localhost:8081"),
+		Timeout:          time.Duration(utils.GetEnv("SONG_INFO_API_TIMEOUT_MS", 5000)) * time.Millisecond,
+		MaxRetries:       utils.GetEnv("SONG_INFO_API_MAX_RETRIES", 3),
+		RetryDelay:       time.Duration(utils.GetEnv("SONG_INFO_API_RETRY_DELAY_MS", 200)) * time.Millisecond,
+		FailureThreshold: utils.GetEnv("SONG_INFO_API_FAILURE_THRESHOLD", 5),
+		OpenDuration:     time.Duration(utils.GetEnv("SONG_INFO_API_OPEN_DURATION_MS", 30000)) * time.Millisecond,
+	}
+}
+
+// SongInfoClient fetches song details from the external song info API. Transient failures are retried
+// with exponential backoff and full jitter; calls that still fail count towards the circuit breaker.
+type SongInfoClient struct {
+	logger     *zap.Logger
+	config     *SongInfoClientConfig
+	httpClient *http.Client
+	Breaker    *CircuitBreaker
+}
+
+func NewSongInfoClient(logger *zap.Logger, config *SongInfoClientConfig) *SongInfoClient {
+	return &SongInfoClient{
+		logger:     logger,
+		config:     config,
+		httpClient: &http.Client{Timeout: config.Timeout},
+		Breaker:    NewCircuitBreaker(config.FailureThreshold, config.OpenDuration),
+	}
+}
+
+func (c *SongInfoClient) Name() string {
+	return "api"
+}
+
+func (c *SongInfoClient) Enrich(ctx context.Context, group, song string) (*models.SongDetail, error) {
+	return c.GetSongInfo(ctx, group, song)
+}
+
+func (c *SongInfoClient) GetSongInfo(ctx context.Context, group, song string) (*models.SongDetail, error) {
+	if err := c.Breaker.Allow(); err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= c.config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			backoff := c.config.RetryDelay << (attempt - 1)
+			select {
+			case <-ctx.Done():
+				c.Breaker.Abandon()
+				return nil, ctx.Err()
+			case <-time.After(time.Duration(rand.Int63n(int64(backoff) + 1))):
+			}
+		}
+
+		detail, err := c.fetch(ctx, group, song)
+		if err == nil {
+			c.Breaker.Success()
+			return detail, nil
+		}
+		if ctx.Err() != nil {
+			// The caller gave up; that says nothing about the API.
+			c.Breaker.Abandon()
+			return nil, ctx.Err()
+		}
+		if !errors.Is(err, ErrSongInfoUnavailable) {
+			c.Breaker.Success()
+			return nil, err
+		}
+		c.logger.Warn("Song info request failed", zap.Int("attempt", attempt+1), zap.Error(err))
+		lastErr = err
+	}
+
+	c.Breaker.Failure()
+	return nil, lastErr
+}
+
+func (c *SongInfoClient) fetch(ctx context.Context, group, song string) (*models.SongDetail, error) {
+	query := url.Values{}
+	query.Set("group", group)
+	query.Set("song", song)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.Host+"/info?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSongInfoRejected, err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSongInfoUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrSongInfoNotFound
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return nil, fmt.Errorf("%w: %s", ErrSongInfoUnavailable, resp.Status)
+	case resp.StatusCode >= http.StatusBadRequest:
+		return nil, fmt.Errorf("%w: %s", ErrSongInfoRejected, resp.Status)
+	}
+
+	var detail models.SongDetail
+	if err := json.NewDecoder(resp.Body).Decode(&detail); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode song details: %v", ErrSongInfoRejected, err)
+	}
+	return &detail, nil
+}
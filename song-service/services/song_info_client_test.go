@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestSongInfoClient(t *testing.T, statuses ...int) (*SongInfoClient, *int) {
+	t.Helper()
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := statuses[min(calls, len(statuses)-1)]
+		calls++
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`{"releaseDate":"16.07.2006","text":"Ooh baby","link":"https://example.com"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewSongInfoClient(zap.NewNop(), &SongInfoClientConfig{
+		Host:             server.URL,
+		Timeout:          time.Second,
+		MaxRetries:       2,
+		RetryDelay:       time.Millisecond,
+		FailureThreshold: 1,
+		OpenDuration:     time.Minute,
+	})
+	return client, &calls
+}
+
+func TestSongInfoClientRetriesServerErrors(t *testing.T) {
+	client, calls := newTestSongInfoClient(t, http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK)
+
+	detail, err := client.GetSongInfo(context.Background(), "Muse", "Supermassive Black Hole")
+	if err != nil {
+		t.Fatalf("GetSongInfo() error = %v", err)
+	}
+	if detail.Text != "Ooh baby" {
+		t.Errorf("GetSongInfo() text = %q, want %q", detail.Text, "Ooh baby")
+	}
+	if *calls != 3 {
+		t.Errorf("API called %d times, want 3", *calls)
+	}
+	if state := client.Breaker.State(); state != CircuitClosed {
+		t.Errorf("breaker is %s, want %s", state, CircuitClosed)
+	}
+}
+
+func TestSongInfoClientDoesNotRetryClientErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		want   error
+	}{
+		{name: "not found", status: http.StatusNotFound, want: ErrSongInfoNotFound},
+		{name: "bad request", status: http.StatusBadRequest, want: ErrSongInfoRejected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, calls := newTestSongInfoClient(t, tt.status)
+
+			if _, err := client.GetSongInfo(context.Background(), "Muse", "Unknown"); !errors.Is(err, tt.want) {
+				t.Errorf("GetSongInfo() error = %v, want %v", err, tt.want)
+			}
+			if *calls != 1 {
+				t.Errorf("API called %d times, want 1", *calls)
+			}
+			if state := client.Breaker.State(); state != CircuitClosed {
+				t.Errorf("breaker is %s, want %s", state, CircuitClosed)
+			}
+		})
+	}
+}
+
+func TestSongInfoClientOpensBreaker(t *testing.T) {
+	client, calls := newTestSongInfoClient(t, http.StatusServiceUnavailable)
+
+	if _, err := client.GetSongInfo(context.Background(), "Muse", "Uprising"); !errors.Is(err, ErrSongInfoUnavailable) {
+		t.Fatalf("GetSongInfo() error = %v, want %v", err, ErrSongInfoUnavailable)
+	}
+	if *calls != 3 {
+		t.Errorf("API called %d times, want 3", *calls)
+	}
+	if _, err := client.GetSongInfo(context.Background(), "Muse", "Uprising"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("GetSongInfo() with an open breaker error = %v, want %v", err, ErrCircuitOpen)
+	}
+	if *calls != 3 {
+		t.Errorf("API called %d times with an open breaker, want 3", *calls)
+	}
+}
+
+func TestSongInfoClientIgnoresCancellation(t *testing.T) {
+	client, _ := newTestSongInfoClient(t, http.StatusServiceUnavailable)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.GetSongInfo(ctx, "Muse", "Uprising"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetSongInfo() error = %v, want %v", err, context.Canceled)
+	}
+	if state := client.Breaker.State(); state != CircuitClosed {
+		t.Errorf("breaker is %s after a cancelled call, want %s", state, CircuitClosed)
+	}
+}
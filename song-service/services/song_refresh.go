@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"github.com/streadway/amqp"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type RefreshSongRequest struct {
+	JobID  string `json:"jobId"`
+	SongID string `json:"songId"`
+	Origin
+}
+
+// RequestRefresh enqueues a job that enriches the song songID again.
+func (s *SongService) RequestRefresh(songID string, origin Origin) (*models.Job, error) {
+	if err := findSong(s.db, songID); err != nil {
+		return nil, err
+	}
+	return s.Enqueue(models.JobTypeRefreshSong, "refresh_song_queue", func(jobID string) interface{} {
+		return &RefreshSongRequest{
+			JobID:  jobID,
+			SongID: songID,
+			Origin: origin,
+		}
+	})
+}
+
+// refreshSong asks the enrichers for the details of the song songID and applies them in a transaction
+// started by transact, recording the change as a revision made by origin.
+func (s *SongService) refreshSong(ctx context.Context, songID string, origin Origin, transact func(fn func(tx *gorm.DB) error) error) error {
+	var song models.Song
+	if err := s.db.Where("id = ?", songID).First(&song).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSongNotFound
+		}
+		return fmt.Errorf("failed to find song: %w", err)
+	}
+
+	detail, err := s.enricher.Enrich(ctx, song.GroupName, song.SongName)
+	if err != nil {
+		// Nobody was asked while the breaker is open or the caller gave up.
+		if !errors.Is(err, ErrCircuitOpen) && ctx.Err() == nil {
+			s.recordAttempt(song.ID)
+		}
+		return err
+	}
+
+	if err := transact(func(tx *gorm.DB) error {
+		if err := s.applySongDetail(tx, &song, detail); err != nil {
+			return err
+		}
+		return s.revisions.Record(tx, song.ID, models.RevisionActionRefresh, origin)
+	}); err != nil {
+		return err
+	}
+
+	s.invalidate(songID)
+	return nil
+}
+
+// recordAttempt bumps enriched_at of the song songID after a failed enrichment, so that the scheduled
+// refresh does not ask again before the song is due and failing songs do not crowd out the others.
+func (s *SongService) recordAttempt(songID uint) {
+	if err := s.db.Model(&models.Song{}).Where("id = ?", songID).Update("enriched_at", time.Now()).Error; err != nil {
+		s.logger.Error("Failed to record enrichment attempt", zap.Uint("songId", songID), zap.Error(err))
+	}
+}
+
+// applySongDetail stores detail on song. Empty fields keep the current value, and so do the verses when
+// detail has no text.
+func (s *SongService) applySongDetail(tx *gorm.DB, song *models.Song, detail *models.SongDetail) error {
+	updates := map[string]interface{}{"enriched_at": time.Now()}
+	if releaseDate := s.parseReleaseDate(detail.ReleaseDate); !releaseDate.IsZero() && !releaseDate.Equal(song.ReleaseDate.Time) {
+		updates["release_date"] = releaseDate
+	}
+	if detail.Link != "" && detail.Link != song.Link {
+		updates["link"] = detail.Link
+	}
+	if err := tx.Model(&models.Song{}).Where("id = ?", song.ID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to update song: %w", err)
+	}
+
+	texts := splitVerses(detail.Text)
+	if len(texts) == 0 {
+		return nil
+	}
+	return s.replaceVerses(tx, song.ID, texts)
+}
+
+// replaceVerses makes texts the verses of the song songID. Verses are diffed in order: changed verses are
+// updated, new ones created and surplus ones deleted.
+func (s *SongService) replaceVerses(tx *gorm.DB, songID uint, texts []string) error {
+	var verses []*models.Verse
+	if err := tx.Where("song_id = ?", songID).Order("id").Find(&verses).Error; err != nil {
+		return fmt.Errorf("failed to find verses: %w", err)
+	}
+	for i, text := range texts {
+		if i >= len(verses) {
+			if err := tx.Create(&models.Verse{SongID: songID, Text: text}).Error; err != nil {
+				return fmt.Errorf("failed to create verse: %w", err)
+			}
+			continue
+		}
+		if verses[i].Text != text {
+			if err := tx.Model(verses[i]).Update("text", text).Error; err != nil {
+				return fmt.Errorf("failed to update verse: %w", err)
+			}
+		}
+	}
+
+	if len(verses) > len(texts) {
+		surplus := make([]uint, 0, len(verses)-len(texts))
+		for _, verse := range verses[len(texts):] {
+			surplus = append(surplus, verse.ID)
+		}
+		if err := tx.Where("id IN ?", surplus).Delete(&models.Verse{}).Error; err != nil {
+			return fmt.Errorf("failed to delete verses: %w", err)
+		}
+	}
+	return nil
+}
+
+func splitVerses(text string) []string {
+	var verses []string
+	for _, verse := range strings.Split(text, "\n\n") {
+		if strings.TrimSpace(verse) != "" {
+			verses = append(verses, verse)
+		}
+	}
+	return verses
+}
+
+func (s *SongService) handleRefreshSong(d amqp.Delivery) error {
+	var req RefreshSongRequest
+	if err := json.Unmarshal(d.Body, &req); err != nil {
+		s.logger.Error("Failed to unmarshal refresh song request", zap.Error(err))
+		return permanent(err)
+	}
+	if s.isProcessed(d) {
+		s.logger.Info("Skipping duplicate message", zap.String("queue", "refresh_song_queue"), zap.String("messageId", d.MessageId))
+		return nil
+	}
+	s.jobs.MarkRunning(req.JobID)
+
+	processed := true
+	err := s.refreshSong(context.Background(), req.SongID, req.Origin, func(fn func(tx *gorm.DB) error) error {
+		var err error
+		processed, err = s.processOnce("refresh_song_queue", d, fn)
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Failed to refresh song", zap.Error(err))
+		switch {
+		case errors.Is(err, ErrCircuitOpen):
+			err = deferred(err)
+		case errors.Is(err, ErrSongNotFound), errors.Is(err, ErrSongInfoNotFound), errors.Is(err, ErrSongInfoRejected):
+			err = permanent(err)
+		}
+		return s.failJob("refresh_song_queue", req.JobID, d, err)
+	}
+	if !processed {
+		s.logger.Info("Skipping duplicate message", zap.String("queue", "refresh_song_queue"), zap.String("messageId", d.MessageId))
+		return nil
+	}
+
+	s.jobs.MarkSucceeded(req.JobID, parseSongID(req.SongID))
+	return nil
+}
+
+// RunRefresh enriches songs with missing or stale details again every RefreshInterval until ctx is done.
+// Songs are refreshed in place rather than through the queue, a batch of RefreshBatchSize at a time.
+func (s *SongService) RunRefresh(ctx context.Context) {
+	if s.config.RefreshInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.config.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.refreshDue(ctx); err != nil {
+				s.logger.Error("Failed to refresh songs", zap.Error(err))
+			}
+		}
+	}
+}
+
+// refreshDue refreshes songs never enriched, enriched longer than RefreshStaleAfter ago, or missing a
+// release date, link or lyrics and enriched longer than RefreshMissingAfter ago.
+func (s *SongService) refreshDue(ctx context.Context) error {
+	now := time.Now()
+	var ids []uint
+	err := s.db.Model(&models.Song{}).
+		Where(`(enriched_at IS NULL OR enriched_at < ? OR (enriched_at < ? AND (
+    release_date IS NULL OR COALESCE(link, '') = ''
+        OR NOT EXISTS (SELECT 1 FROM verses WHERE verses.song_id = songs.id AND verses.deleted_at IS NULL))))`,
+			now.Add(-s.config.RefreshStaleAfter), now.Add(-s.config.RefreshMissingAfter)).
+		Order("enriched_at NULLS FIRST").
+		Order("id").
+		Limit(s.config.RefreshBatchSize).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return err
+	}
+
+	refreshed := 0
+	for _, id := range ids {
+		err := s.refreshSong(ctx, strconv.FormatUint(uint64(id), 10), Origin{Actor: SystemActor}, func(fn func(tx *gorm.DB) error) error {
+			return s.db.Transaction(fn)
+		})
+		switch {
+		case err == nil:
+			refreshed++
+		case errors.Is(err, ErrCircuitOpen), ctx.Err() != nil:
+			return err
+		case errors.Is(err, ErrSongInfoNotFound):
+			s.logger.Info("No details found for song", zap.Uint("songId", id))
+		default:
+			s.logger.Warn("Failed to refresh song", zap.Uint("songId", id), zap.Error(err))
+		}
+	}
+	if len(ids) > 0 {
+		s.logger.Info("Refreshed songs", zap.Int("due", len(ids)), zap.Int("refreshed", refreshed))
+	}
+	return nil
+}
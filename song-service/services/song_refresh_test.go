@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"gorm.io/gorm"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSplitVerses(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{text: "", want: nil},
+		{text: "one verse", want: []string{"one verse"}},
+		{text: "first\nline\n\nsecond", want: []string{"first\nline", "second"}},
+		{text: "\n\nfirst\n\n \n\nsecond\n\n", want: []string{"first", "second"}},
+	}
+	for _, tt := range tests {
+		if got := splitVerses(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitVerses(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func refreshInTransaction(db *gorm.DB) func(fn func(tx *gorm.DB) error) error {
+	return func(fn func(tx *gorm.DB) error) error {
+		return db.Transaction(fn)
+	}
+}
+
+func verseTexts(t *testing.T, db *gorm.DB, songID uint) []string {
+	t.Helper()
+	var texts []string
+	if err := db.Model(&models.Verse{}).Where("song_id = ?", songID).Order("id").Pluck("text", &texts).Error; err != nil {
+		t.Fatalf("failed to read verses: %v", err)
+	}
+	return texts
+}
+
+func TestRequestRefresh(t *testing.T) {
+	service, db := newTestSongService(t, &stubEnricher{name: "stub"})
+	song := createSong(t, db, "Muse", "Uprising")
+	songID := strconv.FormatUint(uint64(song.ID), 10)
+
+	job, err := service.RequestRefresh(songID, Origin{})
+	if err != nil {
+		t.Fatalf("RequestRefresh() error = %v", err)
+	}
+	if job.Type != models.JobTypeRefreshSong {
+		t.Errorf("job type = %q, want %q", job.Type, models.JobTypeRefreshSong)
+	}
+	var message models.OutboxMessage
+	if err := db.Where("queue = ?", "refresh_song_queue").First(&message).Error; err != nil {
+		t.Fatalf("no refresh message in the outbox: %v", err)
+	}
+	var req RefreshSongRequest
+	if err := json.Unmarshal(message.Payload, &req); err != nil || req.JobID != job.ID || req.SongID != songID {
+		t.Errorf("outbox payload = %s, want job %s and song %s", message.Payload, job.ID, songID)
+	}
+
+	if _, err := service.RequestRefresh("0", Origin{}); !errors.Is(err, ErrSongNotFound) {
+		t.Errorf("RequestRefresh() of a missing song error = %v, want %v", err, ErrSongNotFound)
+	}
+}
+
+func TestRefreshSongAppliesDetails(t *testing.T) {
+	enricher := &stubEnricher{name: "stub", detail: &models.SongDetail{Text: "first\n\nSecond changed", Link: "https://example.com/new"}}
+	service, db := newTestSongService(t, enricher)
+
+	song := createSong(t, db, "Muse", "Uprising")
+	for _, text := range []string{"first", "second", "third"} {
+		if err := db.Create(&models.Verse{SongID: song.ID, Text: text}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := service.refreshSong(context.Background(), strconv.FormatUint(uint64(song.ID), 10), Origin{}, refreshInTransaction(db)); err != nil {
+		t.Fatalf("refreshSong() error = %v", err)
+	}
+
+	if got, want := verseTexts(t, db, song.ID), []string{"first", "Second changed"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("verses = %q, want %q", got, want)
+	}
+	var refreshed models.Song
+	db.First(&refreshed, song.ID)
+	if refreshed.Link != "https://example.com/new" {
+		t.Errorf("link = %q, want https://example.com/new", refreshed.Link)
+	}
+	if refreshed.EnrichedAt == nil {
+		t.Error("enriched_at was not set")
+	}
+}
+
+func TestRefreshSongRecordsFailedAttempts(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		record bool
+	}{
+		{name: "unknown song", err: ErrSongInfoNotFound, record: true},
+		{name: "rejected", err: ErrSongInfoRejected, record: true},
+		{name: "unavailable", err: ErrSongInfoUnavailable, record: true},
+		{name: "breaker open", err: ErrCircuitOpen, record: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, db := newTestSongService(t, &stubEnricher{name: "stub", err: tt.err})
+			song := createSong(t, db, "Muse", "Uprising")
+
+			err := service.refreshSong(context.Background(), strconv.FormatUint(uint64(song.ID), 10), Origin{}, refreshInTransaction(db))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("refreshSong() error = %v, want %v", err, tt.err)
+			}
+			var refreshed models.Song
+			db.First(&refreshed, song.ID)
+			if recorded := refreshed.EnrichedAt != nil; recorded != tt.record {
+				t.Errorf("attempt recorded = %v, want %v", recorded, tt.record)
+			}
+		})
+	}
+}
+
+func TestRefreshSongOfMissingSong(t *testing.T) {
+	service, db := newTestSongService(t, &stubEnricher{name: "stub"})
+
+	if err := service.refreshSong(context.Background(), "0", Origin{}, refreshInTransaction(db)); !errors.Is(err, ErrSongNotFound) {
+		t.Errorf("refreshSong() of a missing song error = %v, want %v", err, ErrSongNotFound)
+	}
+}
+
+func TestRefreshDueSkipsFreshSongs(t *testing.T) {
+	enricher := &stubEnricher{name: "stub", err: ErrSongInfoUnavailable}
+	service, db := newTestSongService(t, enricher)
+
+	createSong(t, db, "Muse", "Never Enriched")
+	stale := createSong(t, db, "Muse", "Stale")
+	db.Model(stale).Update("enriched_at", time.Now().Add(-48*time.Hour))
+	fresh := createSong(t, db, "Muse", "Fresh")
+	db.Model(fresh).Updates(map[string]interface{}{
+		"enriched_at":  time.Now(),
+		"release_date": "2006-07-16",
+		"link":         "https://example.com",
+	})
+	db.Create(&models.Verse{SongID: fresh.ID, Text: "verse"})
+
+	if err := service.refreshDue(context.Background()); err != nil {
+		t.Fatalf("refreshDue() error = %v", err)
+	}
+	if enricher.calls != 2 {
+		t.Errorf("enricher asked %d times, want 2", enricher.calls)
+	}
+
+	// The attempts were recorded, so nothing is due right away.
+	if err := service.refreshDue(context.Background()); err != nil {
+		t.Fatalf("refreshDue() error = %v", err)
+	}
+	if enricher.calls != 2 {
+		t.Errorf("enricher asked %d times after a second run, want 2", enricher.calls)
+	}
+}
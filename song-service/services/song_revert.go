@@ -0,0 +1,124 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"github.com/streadway/amqp"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"time"
+)
+
+type RevertSongRequest struct {
+	JobID    string `json:"jobId"`
+	SongID   string `json:"songId"`
+	Revision string `json:"revision"`
+	Origin
+}
+
+// RequestRevert enqueues a job that restores the song songID to its state as of revision.
+func (s *SongService) RequestRevert(songID, revision string, origin Origin) (*models.Job, error) {
+	if err := s.db.Unscoped().Where("id = ?", songID).First(&models.Song{}).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSongNotFound
+		}
+		return nil, fmt.Errorf("failed to find song: %w", err)
+	}
+	if _, err := s.revisions.FindSnapshot(s.db, songID, revision); err != nil {
+		return nil, err
+	}
+	return s.Enqueue(models.JobTypeRevertSong, "revert_song_queue", func(jobID string) interface{} {
+		return &RevertSongRequest{
+			JobID:    jobID,
+			SongID:   songID,
+			Revision: revision,
+			Origin:   origin,
+		}
+	})
+}
+
+// revertSong restores the fields, verses and deletion of the song to the snapshot of the requested
+// revision and records the result as a new revision.
+func (s *SongService) revertSong(tx *gorm.DB, req *RevertSongRequest) error {
+	var song models.Song
+	if err := tx.Unscoped().Where("id = ?", req.SongID).First(&song).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSongNotFound
+		}
+		return fmt.Errorf("failed to find song: %w", err)
+	}
+	snapshot, err := s.revisions.FindSnapshot(tx, req.SongID, req.Revision)
+	if err != nil {
+		return err
+	}
+
+	key := models.NormalizedKey(snapshot.GroupName, snapshot.SongName)
+	if !snapshot.Deleted {
+		existing, err := s.findByKey(tx, key, song.ID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return &DuplicateSongError{ID: existing.ID}
+		}
+	}
+
+	var deletedAt interface{}
+	if snapshot.Deleted {
+		deletedAt = time.Now()
+		if song.DeletedAt.Valid {
+			deletedAt = song.DeletedAt.Time
+		}
+	}
+	err = tx.Unscoped().Model(&models.Song{}).Where("id = ?", song.ID).Updates(map[string]interface{}{
+		"group_name":     snapshot.GroupName,
+		"song_name":      snapshot.SongName,
+		"release_date":   snapshot.ReleaseDate,
+		"link":           snapshot.Link,
+		"normalized_key": key,
+		"deleted_at":     deletedAt,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to revert song: %w", err)
+	}
+
+	if snapshot.GroupName != song.GroupName {
+		if err := s.artists.ReplaceGroupCredits(tx, song.ID, snapshot.GroupName); err != nil {
+			return err
+		}
+	}
+	if err := s.replaceVerses(tx, song.ID, snapshot.Verses); err != nil {
+		return err
+	}
+	return s.revisions.Record(tx, song.ID, models.RevisionActionRevert, req.Origin)
+}
+
+func (s *SongService) handleRevertSong(d amqp.Delivery) error {
+	var req RevertSongRequest
+	if err := json.Unmarshal(d.Body, &req); err != nil {
+		s.logger.Error("Failed to unmarshal revert song request", zap.Error(err))
+		return permanent(err)
+	}
+	s.jobs.MarkRunning(req.JobID)
+
+	processed, err := s.processOnce("revert_song_queue", d, func(tx *gorm.DB) error {
+		return s.revertSong(tx, &req)
+	})
+	if err != nil {
+		s.logger.Error("Failed to revert song", zap.Error(err))
+		if errors.Is(err, ErrSongNotFound) || errors.Is(err, ErrRevisionNotFound) || errors.Is(err, ErrDuplicateSong) {
+			err = permanent(err)
+		}
+		return s.failJob("revert_song_queue", req.JobID, d, err)
+	}
+	if !processed {
+		s.logger.Info("Skipping duplicate message", zap.String("queue", "revert_song_queue"), zap.String("messageId", d.MessageId))
+		return nil
+	}
+
+	s.invalidate(req.SongID)
+	s.jobs.MarkSucceeded(req.JobID, parseSongID(req.SongID))
+	return nil
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"errors"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"gorm.io/gorm"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestRevertSongRestoresSnapshot(t *testing.T) {
+	service, db := newTestSongService(t, &stubEnricher{name: "stub"})
+	song := createSong(t, db, "Muse", "Uprising")
+	songID := strconv.FormatUint(uint64(song.ID), 10)
+	db.Create(&models.Verse{SongID: song.ID, Text: "Paranoia is in bloom"})
+	record := func(action string) {
+		t.Helper()
+		if err := service.revisions.Record(db, song.ID, action, Origin{Actor: "tester"}); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+	record(models.RevisionActionCreate)
+
+	if err := service.UpdateSong(&UpdateSongRequest{SongID: songID, Song: &models.Song{SongName: "Resistance", Link: "https://example.com"}}); err != nil {
+		t.Fatalf("UpdateSong() error = %v", err)
+	}
+	if err := service.DeleteSong(&DeleteSongRequest{SongId: songID}); err != nil {
+		t.Fatalf("DeleteSong() error = %v", err)
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return service.revertSong(tx, &RevertSongRequest{SongID: songID, Revision: "1", Origin: Origin{Actor: "tester"}})
+	})
+	if err != nil {
+		t.Fatalf("revertSong() error = %v", err)
+	}
+
+	var reverted models.Song
+	if err := db.First(&reverted, song.ID).Error; err != nil {
+		t.Fatalf("reverted song is not visible: %v", err)
+	}
+	if reverted.SongName != "Uprising" || reverted.Link != "" || reverted.NormalizedKey != song.NormalizedKey {
+		t.Errorf("reverted song = %q %q %q, want the first revision", reverted.SongName, reverted.Link, reverted.NormalizedKey)
+	}
+	if got, want := verseTexts(t, db, song.ID), []string{"Paranoia is in bloom"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("verses = %q, want %q", got, want)
+	}
+
+	var actions []string
+	db.Model(&models.SongRevision{}).Where("song_id = ?", song.ID).Order("revision").Pluck("action", &actions)
+	want := []string{models.RevisionActionCreate, models.RevisionActionUpdate, models.RevisionActionDelete, models.RevisionActionRevert}
+	if !reflect.DeepEqual(actions, want) {
+		t.Errorf("revisions = %v, want %v", actions, want)
+	}
+}
+
+func TestRevertSongRejectsDuplicates(t *testing.T) {
+	service, db := newTestSongService(t, &stubEnricher{name: "stub"})
+	song := createSong(t, db, "Muse", "Uprising")
+	songID := strconv.FormatUint(uint64(song.ID), 10)
+	if err := service.revisions.Record(db, song.ID, models.RevisionActionCreate, Origin{}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := service.UpdateSong(&UpdateSongRequest{SongID: songID, Song: &models.Song{SongName: "Resistance"}}); err != nil {
+		t.Fatalf("UpdateSong() error = %v", err)
+	}
+	other := createSong(t, db, "Muse", "Uprising")
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return service.revertSong(tx, &RevertSongRequest{SongID: songID, Revision: "1"})
+	})
+	var duplicate *DuplicateSongError
+	if !errors.As(err, &duplicate) || duplicate.ID != other.ID {
+		t.Errorf("revertSong() error = %v, want a duplicate of song %d", err, other.ID)
+	}
+}
+
+func TestRequestRevert(t *testing.T) {
+	service, db := newTestSongService(t, &stubEnricher{name: "stub"})
+	song := createSong(t, db, "Muse", "Uprising")
+	songID := strconv.FormatUint(uint64(song.ID), 10)
+	if err := service.revisions.Record(db, song.ID, models.RevisionActionCreate, Origin{}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	if _, err := service.RequestRevert(songID, "1", Origin{}); err != nil {
+		t.Errorf("RequestRevert() error = %v", err)
+	}
+	if _, err := service.RequestRevert(songID, "2", Origin{}); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("RequestRevert() of a missing revision error = %v, want %v", err, ErrRevisionNotFound)
+	}
+	if _, err := service.RequestRevert("0", "1", Origin{}); !errors.Is(err, ErrSongNotFound) {
+		t.Errorf("RequestRevert() of a missing song error = %v, want %v", err, ErrSongNotFound)
+	}
+}
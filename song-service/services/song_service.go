@@ -1,96 +1,261 @@
 package services
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/SZabrodskii/music-library/utils"
+	"github.com/SZabrodskii/music-library/utils/filters"
 	"github.com/SZabrodskii/music-library/utils/models"
+	"github.com/SZabrodskii/music-library/utils/pagination"
 	"github.com/SZabrodskii/music-library/utils/providers"
 	"github.com/streadway/amqp"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
-	"net/http"
+	"gorm.io/gorm/clause"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type SongServiceConfig struct {
-	SongInfoAPIHost string
+	CursorSecret string
+	// RefreshInterval is how often songs with missing or stale details are enriched again; 0 disables it.
+	RefreshInterval     time.Duration
+	RefreshStaleAfter   time.Duration
+	RefreshMissingAfter time.Duration
+	RefreshBatchSize    int
 }
 
 func NewSongServiceConfig() *SongServiceConfig {
 	return &SongServiceConfig{
-		SongInfoAPIHost: utils.GetEnv("SONG_INFO_API_HOST", "localhost:8081"),
+		CursorSecret:        utils.GetEnv("CURSOR_SECRET", "music-library-cursor-secret"),
+		RefreshInterval:     time.Duration(utils.GetEnv("SONG_REFRESH_INTERVAL_MINUTES", 60)) * time.Minute,
+		RefreshStaleAfter:   time.Duration(utils.GetEnv("SONG_REFRESH_STALE_AFTER_HOURS", 720)) * time.Hour,
+		RefreshMissingAfter: time.Duration(utils.GetEnv("SONG_REFRESH_MISSING_AFTER_HOURS", 24)) * time.Hour,
+		RefreshBatchSize:    utils.GetEnv("SONG_REFRESH_BATCH_SIZE", 50),
 	}
 }
 
+var (
+	ErrSongNotFound  = errors.New("song not found")
+	ErrDuplicateSong = errors.New("song already exists")
+)
+
+// DuplicateSongError reports the existing song that has the same normalized group and name.
+type DuplicateSongError struct {
+	ID uint
+}
+
+func (e *DuplicateSongError) Error() string {
+	return ErrDuplicateSong.Error()
+}
+
+func (e *DuplicateSongError) Is(target error) bool {
+	return target == ErrDuplicateSong
+}
+
 type SongService struct {
 	logger          *zap.Logger
 	db              *gorm.DB
 	queue           *providers.RabbitMQProvider
+	cache           *providers.CacheProvider
+	jobs            *JobService
+	outbox          *OutboxService
+	artists         *ArtistService
+	albums          *AlbumService
+	genres          *GenreService
+	tags            *TagService
+	revisions       *RevisionService
+	enricher        Enricher
 	ConsumerManager *ConsumerManager
 	config          *SongServiceConfig
 }
 
-type ConsumerManager struct {
-	queue    *providers.RabbitMQProvider
-	logger   *zap.Logger
-	db       *gorm.DB
-	handlers map[string]func(amqp.Delivery)
-}
-
-func NewSongService(logger *zap.Logger, db *gorm.DB, queue *providers.RabbitMQProvider, config *SongServiceConfig) *SongService {
-	consumerManager := NewConsumerManager(logger, db, queue)
+func NewSongService(logger *zap.Logger, db *gorm.DB, queue *providers.RabbitMQProvider, cache *providers.CacheProvider, jobs *JobService, outbox *OutboxService, artists *ArtistService, albums *AlbumService, genres *GenreService, tags *TagService, revisions *RevisionService, songInfo *SongInfoClient, enricher Enricher, config *SongServiceConfig, consumerConfig *ConsumerManagerConfig) *SongService {
+	consumerManager := NewConsumerManager(logger, db, queue, consumerConfig)
+	songInfo.Breaker.OnChange(func(state CircuitState) {
+		logger.Warn("Song info API circuit changed", zap.String("state", string(state)))
+		// Without other enrichers to fall back on, adding and refreshing a song need the song info API, so
+		// stop taking those requests while it is down.
+		if enricher.Name() != songInfo.Name() {
+			return
+		}
+		for _, queueName := range []string{"add_song_queue", "refresh_song_queue"} {
+			if state == CircuitOpen {
+				consumerManager.Pause(queueName)
+			} else {
+				consumerManager.Resume(queueName)
+			}
+		}
+	})
 	return &SongService{
 		logger:          logger,
 		db:              db,
 		queue:           queue,
+		cache:           cache,
+		jobs:            jobs,
+		outbox:          outbox,
+		artists:         artists,
+		albums:          albums,
+		genres:          genres,
+		tags:            tags,
+		revisions:       revisions,
+		enricher:        enricher,
 		ConsumerManager: consumerManager,
 		config:          config,
 	}
 }
 
-func NewConsumerManager(logger *zap.Logger, db *gorm.DB, queue *providers.RabbitMQProvider) *ConsumerManager {
-	return &ConsumerManager{
-		queue:    queue,
-		logger:   logger,
-		db:       db,
-		handlers: make(map[string]func(amqp.Delivery)),
+type GetSongsRequest struct {
+	Page     string            `json:"page"`
+	PageSize string            `json:"pageSize"`
+	Cursor   string            `json:"cursor"`
+	Limit    string            `json:"limit"`
+	Filters  []*filters.Filter `json:"filters"`
+	Sort     []filters.SortKey `json:"sort"`
+}
+
+// CursorMode reports whether the request asks for keyset pagination instead of page numbers.
+func (r *GetSongsRequest) CursorMode() bool {
+	return r.Cursor != "" || r.Limit != ""
+}
+
+func (s *SongService) GetSongs(req *GetSongsRequest) ([]*models.Song, *pagination.Pagination, error) {
+	songs := make([]*models.Song, 0)
+	query := filters.Apply(s.db.Model(&models.Song{}), req.Filters)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, nil, err
+	}
+
+	links := url.Values{"filters": filters.Strings(req.Filters), "sort": {filters.SortString(req.Sort)}}
+	query = filters.ApplySort(query, req.Sort)
+	if req.CursorMode() {
+		return s.getSongsAfterCursor(query, req, total, links)
+	}
+
+	page, pageSize := pagination.Normalize(req.Page, req.PageSize)
+	if err := query.Offset(pagination.Offset(page, pageSize)).Limit(pageSize).Find(&songs).Error; err != nil {
+		return nil, nil, err
+	}
+	if err := s.loadLabels(songs); err != nil {
+		return nil, nil, err
 	}
+
+	meta := pagination.New(total, page, pageSize, links)
+	return songs, &meta, nil
 }
 
-func (cm *ConsumerManager) RegisterHandler(queueName string, handler func(amqp.Delivery)) {
-	cm.handlers[queueName] = handler
-	cm.queue.Consume(queueName, handler)
+func (s *SongService) getSongsAfterCursor(query *gorm.DB, req *GetSongsRequest, total int64, links url.Values) ([]*models.Song, *pagination.Pagination, error) {
+	songs := make([]*models.Song, 0)
+	limit := pagination.NormalizeLimit(req.Limit)
+	secret := []byte(s.config.CursorSecret)
+	sort := filters.SortString(req.Sort)
+
+	if req.Cursor != "" {
+		cursor, err := pagination.DecodeCursor(req.Cursor, secret)
+		if err != nil {
+			return nil, nil, err
+		}
+		if cursor.Sort != sort || len(cursor.Values) != len(req.Sort) {
+			return nil, nil, fmt.Errorf("%w: cursor was issued for sort %q", pagination.ErrInvalidCursor, cursor.Sort)
+		}
+		query = filters.ApplyAfter(query, req.Sort, cursor.Values)
+	}
+
+	if err := query.Limit(limit + 1).Find(&songs).Error; err != nil {
+		return nil, nil, err
+	}
+	if err := s.loadLabels(songs); err != nil {
+		return nil, nil, err
+	}
+
+	var nextCursor string
+	if len(songs) > limit {
+		songs = songs[:limit]
+		cursor := &pagination.Cursor{Sort: sort, Values: filters.SortValues(req.Sort, songs[limit-1])}
+		encoded, err := pagination.EncodeCursor(cursor, secret)
+		if err != nil {
+			return nil, nil, err
+		}
+		nextCursor = encoded
+	}
+
+	meta := pagination.NewCursor(total, limit, nextCursor, links)
+	return songs, &meta, nil
 }
 
-func (cm *ConsumerManager) StartConsumers() {
-	for queueName, handler := range cm.handlers {
-		cm.queue.Consume(queueName, handler)
+// GetSongFacets counts the songs matching filters per genre and per tag.
+func (s *SongService) GetSongFacets(songFilters []*filters.Filter) (*models.SongFacets, error) {
+	songs := filters.Apply(s.db.Model(&models.Song{}), songFilters).Select("songs.id")
+	genres, err := s.genres.Facets(songs)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.tags.Facets(songs)
+	if err != nil {
+		return nil, err
 	}
+	return &models.SongFacets{Genres: genres, Tags: tags}, nil
 }
 
-type GetSongsRequest struct {
-	Page     string   `json:"page"`
-	PageSize string   `json:"pageSize"`
-	Filters  []string `json:"filters"`
+// loadLabels fills the credits, genres and tags of songs.
+func (s *SongService) loadLabels(songs []*models.Song) error {
+	if err := s.artists.LoadCredits(s.db, songs); err != nil {
+		return err
+	}
+	if err := s.genres.LoadGenres(s.db, songs); err != nil {
+		return err
+	}
+	return s.tags.LoadTags(s.db, songs)
 }
 
-func (s *SongService) GetSongs(req *GetSongsRequest) ([]*models.Song, error) {
-	songs := make([]*models.Song, 0)
-	pageInt, _ := strconv.Atoi(req.Page)
-	pageSizeInt, _ := strconv.Atoi(req.PageSize)
+type GetSongRequest struct {
+	SongId string `json:"songId"`
+}
+
+func (s *SongService) GetSong(req *GetSongRequest) (*models.SongWithDetail, error) {
+	var song models.Song
+	if err := s.db.Where("id = ?", req.SongId).First(&song).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSongNotFound
+		}
+		return nil, err
+	}
+	if err := s.loadLabels([]*models.Song{&song}); err != nil {
+		return nil, err
+	}
 
-	filters := req.Filters
-	query := s.db.Offset((pageInt - 1) * pageSizeInt).Limit(pageSizeInt)
-	for _, filter := range filters {
-		query = query.Where(filter)
+	var verses []*models.Verse
+	if err := s.db.Where("song_id = ?", song.ID).Order("id").Find(&verses).Error; err != nil {
+		return nil, err
 	}
-	if err := query.Find(&songs).Error; err != nil {
+
+	texts := make([]string, 0, len(verses))
+	for _, verse := range verses {
+		texts = append(texts, verse.Text)
+	}
+
+	albums, err := s.albums.SongAlbums(song.ID)
+	if err != nil {
 		return nil, err
 	}
 
-	return songs, nil
+	return &models.SongWithDetail{
+		Song:       &song,
+		VerseCount: int64(len(verses)),
+		Detail: models.SongDetail{
+			ReleaseDate: song.ReleaseDate.String(),
+			Text:        strings.Join(texts, "\n\n"),
+			Link:        song.Link,
+		},
+		Albums: albums,
+	}, nil
 }
 
 type GetSongTextRequest struct {
@@ -99,180 +264,497 @@ type GetSongTextRequest struct {
 	PageSize string `json:"pageSize"`
 }
 
-func (s *SongService) GetSongText(req *GetSongTextRequest) ([]*models.Verse, error) {
-	var verses []*models.Verse
-	pageInt, _ := strconv.Atoi(req.Page)
-	pageSizeInt, _ := strconv.Atoi(req.PageSize)
-	if err := s.db.Where("song_id = ?", req.SongId).Offset((pageInt - 1) * pageSizeInt).Limit(pageSizeInt).Find(&verses).Error; err != nil {
-		return nil, err
+func (s *SongService) GetSongText(req *GetSongTextRequest) ([]*models.Verse, *pagination.Pagination, error) {
+	if err := findSong(s.db, req.SongId); err != nil {
+		return nil, nil, err
+	}
+
+	verses := make([]*models.Verse, 0)
+	page, pageSize := pagination.Normalize(req.Page, req.PageSize)
+
+	query := s.db.Model(&models.Verse{}).Where("song_id = ?", req.SongId)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, nil, err
+	}
+	if err := query.Order("id").Offset(pagination.Offset(page, pageSize)).Limit(pageSize).Find(&verses).Error; err != nil {
+		return nil, nil, err
+	}
+
+	meta := pagination.New(total, page, pageSize, url.Values{})
+	return verses, &meta, nil
+}
+
+type SearchLyricsRequest struct {
+	Query    string `json:"q"`
+	Page     string `json:"page"`
+	PageSize string `json:"pageSize"`
+}
+
+const searchLyricsSQL = `
+SELECT s.id AS song_id, s.group_name, s.song_name, m.verse_index, m.rank,
+       ts_headline('simple', m.text, websearch_to_tsquery('simple', @query), 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2') AS snippet
+FROM (
+    SELECT DISTINCT ON (v.song_id) v.song_id, v.text,
+           ts_rank(v.text_search, websearch_to_tsquery('simple', @query)) AS rank,
+           (SELECT COUNT(*) FROM verses p WHERE p.song_id = v.song_id AND p.id < v.id AND p.deleted_at IS NULL) AS verse_index
+    FROM verses v
+    WHERE v.text_search @@ websearch_to_tsquery('simple', @query) AND v.deleted_at IS NULL
+    ORDER BY v.song_id, rank DESC, v.id
+) m
+JOIN songs s ON s.id = m.song_id AND s.deleted_at IS NULL
+ORDER BY m.rank DESC, s.id
+LIMIT @limit OFFSET @offset`
+
+const countLyricsMatchesSQL = `
+SELECT COUNT(DISTINCT v.song_id)
+FROM verses v
+JOIN songs s ON s.id = v.song_id AND s.deleted_at IS NULL
+WHERE v.text_search @@ websearch_to_tsquery('simple', @query) AND v.deleted_at IS NULL`
+
+// SearchLyrics finds songs by a line of their lyrics, returning the best matching verse of each song ranked by relevance.
+func (s *SongService) SearchLyrics(req *SearchLyricsRequest) ([]*models.LyricsMatch, *pagination.Pagination, error) {
+	matches := make([]*models.LyricsMatch, 0)
+	page, pageSize := pagination.Normalize(req.Page, req.PageSize)
+
+	var total int64
+	if err := s.db.Raw(countLyricsMatchesSQL, sql.Named("query", req.Query)).Scan(&total).Error; err != nil {
+		return nil, nil, err
+	}
+
+	args := []interface{}{
+		sql.Named("query", req.Query),
+		sql.Named("limit", pageSize),
+		sql.Named("offset", pagination.Offset(page, pageSize)),
+	}
+	if err := s.db.Raw(searchLyricsSQL, args...).Scan(&matches).Error; err != nil {
+		return nil, nil, err
+	}
+
+	meta := pagination.New(total, page, pageSize, url.Values{"q": {req.Query}})
+	return matches, &meta, nil
+}
+
+type SearchSongsRequest struct {
+	Query    string `json:"q"`
+	Page     string `json:"page"`
+	PageSize string `json:"pageSize"`
+}
+
+const songNameSimilarity = `GREATEST(similarity(songs.group_name, @query), similarity(songs.song_name, @query), similarity(songs.group_name || ' ' || songs.song_name, @query))`
+
+const songNameMatches = `(songs.group_name % @query OR songs.song_name % @query OR (songs.group_name || ' ' || songs.song_name) % @query)`
+
+// SearchSongs is a typo-tolerant search over group and song names using pg_trgm similarity.
+func (s *SongService) SearchSongs(req *SearchSongsRequest) ([]*models.SongMatch, *pagination.Pagination, error) {
+	matches := make([]*models.SongMatch, 0)
+	page, pageSize := pagination.Normalize(req.Page, req.PageSize)
+	query := sql.Named("query", req.Query)
+
+	var total int64
+	if err := s.db.Model(&models.Song{}).Where(songNameMatches, query).Count(&total).Error; err != nil {
+		return nil, nil, err
 	}
-	return verses, nil
+
+	err := s.db.Model(&models.Song{}).
+		Select("songs.*, "+songNameSimilarity+" AS score", query).
+		Where(songNameMatches, query).
+		Order("score DESC, songs.id").
+		Offset(pagination.Offset(page, pageSize)).
+		Limit(pageSize).
+		Scan(&matches).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	meta := pagination.New(total, page, pageSize, url.Values{"q": {req.Query}})
+	return matches, &meta, nil
 }
 
 type DeleteSongRequest struct {
+	JobID  string `json:"jobId"`
 	SongId string `json:"songId"`
+	Origin
+}
+
+// RequestDelete enqueues a job that deletes the song songID.
+func (s *SongService) RequestDelete(songID string, origin Origin) (*models.Job, error) {
+	if err := findSong(s.db, songID); err != nil {
+		return nil, err
+	}
+	return s.Enqueue(models.JobTypeDeleteSong, "delete_song_queue", func(jobID string) interface{} {
+		return &DeleteSongRequest{
+			JobID:  jobID,
+			SongId: songID,
+			Origin: origin,
+		}
+	})
 }
 
 func (s *SongService) DeleteSong(req *DeleteSongRequest) error {
-	if err := s.db.Where("id = ?", req.SongId).Delete(&models.Song{}).Error; err != nil {
-		return err
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return s.deleteSong(tx, req)
+	})
+}
+
+func (s *SongService) deleteSong(tx *gorm.DB, req *DeleteSongRequest) error {
+	result := tx.Where("id = ?", req.SongId).Delete(&models.Song{})
+	if result.Error != nil {
+		return result.Error
 	}
-	return nil
+	if result.RowsAffected == 0 {
+		return ErrSongNotFound
+	}
+	return s.revisions.Record(tx, parseSongID(req.SongId), models.RevisionActionDelete, req.Origin)
 }
 
 type UpdateSongRequest struct {
+	JobID  string       `json:"jobId"`
 	SongID string       `json:"songId"`
 	Song   *models.Song `json:"song"`
+	Origin
 }
 
 func (s *SongService) UpdateSong(req *UpdateSongRequest) error {
-	if err := s.db.Where("id = ?", req.SongID).First(&models.Song{}).Error; err != nil {
-		return fmt.Errorf("song not found: %w", err)
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return s.updateSong(tx, req)
+	})
+}
+
+func (s *SongService) updateSong(tx *gorm.DB, req *UpdateSongRequest) error {
+	var current models.Song
+	if err := tx.Where("id = ?", req.SongID).First(&current).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSongNotFound
+		}
+		return fmt.Errorf("failed to find song: %w", err)
 	}
 
-	if err := s.db.Model(&models.Song{}).Where("id = ?", req.SongID).Updates(req.Song).Error; err != nil {
+	key := models.NormalizedKey(coalesce(req.Song.GroupName, current.GroupName), coalesce(req.Song.SongName, current.SongName))
+	existing, err := s.findByKey(tx, key, current.ID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return &DuplicateSongError{ID: existing.ID}
+	}
+	req.Song.NormalizedKey = key
+
+	if err := tx.Model(&models.Song{}).Where("id = ?", req.SongID).Omit("enriched_at").Updates(req.Song).Error; err != nil {
 		return fmt.Errorf("failed to update song: %w", err)
 	}
+	// The details were fetched for the old name, so a renamed song is due for a refresh.
+	if key != current.NormalizedKey {
+		if err := tx.Model(&models.Song{}).Where("id = ?", current.ID).Update("enriched_at", nil).Error; err != nil {
+			return fmt.Errorf("failed to update song: %w", err)
+		}
+	}
 
-	return nil
+	if req.Song.GroupName != "" && req.Song.GroupName != current.GroupName {
+		if err := s.artists.ReplaceGroupCredits(tx, current.ID, req.Song.GroupName); err != nil {
+			return err
+		}
+	}
+	if err := s.artists.CreditSong(tx, current.ID, req.Song.Artists); err != nil {
+		return err
+	}
+	return s.revisions.Record(tx, current.ID, models.RevisionActionUpdate, req.Origin)
 }
 
-type AddSongRequest struct {
-	Song *models.Song `json:"song"`
-}
+// CheckDuplicate returns a DuplicateSongError if adding song, or applying it as a patch to the song
+// songID when songID is not empty, would duplicate another song.
+func (s *SongService) CheckDuplicate(songID string, song *models.Song) error {
+	group, name := song.GroupName, song.SongName
+	var excludeID uint
+	if songID != "" {
+		var current models.Song
+		if err := s.db.Where("id = ?", songID).First(&current).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrSongNotFound
+			}
+			return fmt.Errorf("failed to find song: %w", err)
+		}
+		excludeID = current.ID
+		group, name = coalesce(group, current.GroupName), coalesce(name, current.SongName)
+	}
 
-func (s *SongService) AddSongToQueue(req *AddSongRequest) error {
-	body, err := json.Marshal(req.Song)
+	existing, err := s.findByKey(s.db, models.NormalizedKey(group, name), excludeID)
 	if err != nil {
 		return err
 	}
-	return s.queue.Publish("add_song_queue", body)
+	if existing != nil {
+		return &DuplicateSongError{ID: existing.ID}
+	}
+	return nil
 }
 
-func (s *SongService) PublishToQueue(queueName string, data interface{}) error {
-	body, err := json.Marshal(data)
-	if err != nil {
-		return err
+func (s *SongService) findByKey(tx *gorm.DB, key string, excludeID uint) (*models.Song, error) {
+	query := tx.Where("normalized_key = ?", key)
+	if excludeID != 0 {
+		query = query.Where("id <> ?", excludeID)
 	}
-	return s.queue.Publish(queueName, body)
+	var song models.Song
+	if err := query.First(&song).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find song: %w", err)
+	}
+	return &song, nil
 }
 
-func (s *SongService) handleAddSong(d amqp.Delivery) {
-	var song models.Song
-	if err := json.Unmarshal(d.Body, &song); err != nil {
-		s.logger.Error("Failed to unmarshal song", zap.Error(err))
-		d.Reject(false)
-		return
+func coalesce(value, fallback string) string {
+	if value == "" {
+		return fallback
 	}
+	return value
+}
 
-	apiURL := s.config.SongInfoAPIHost + "/info"
-	req, err := http.NewRequest("GET", apiURL, nil)
+// parseReleaseDate parses a release date from an enricher. An unknown format is logged and treated as missing
+// rather than failing the song.
+func (s *SongService) parseReleaseDate(value string) models.Date {
+	date, err := models.ParseDate(value)
 	if err != nil {
-		s.logger.Error("Failed to create request", zap.Error(err))
-		d.Reject(false)
-		return
+		s.logger.Warn("Ignoring unparsable release date", zap.String("releaseDate", value), zap.Error(err))
 	}
+	return date
+}
 
-	q := req.URL.Query()
-	q.Add("group", song.GroupName)
-	q.Add("song", song.SongName)
-	req.URL.RawQuery = q.Encode()
+type AddSongRequest struct {
+	JobID string       `json:"jobId"`
+	Song  *models.Song `json:"song"`
+	Origin
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// Enqueue creates a job of jobType and, in the same transaction, an outbox message to queueName with
+// the request built for the job. The outbox relay publishes it after the commit.
+func (s *SongService) Enqueue(jobType, queueName string, request func(jobID string) interface{}) (*models.Job, error) {
+	var job *models.Job
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		job, err = s.jobs.CreateJobTx(tx, jobType)
+		if err != nil {
+			return err
+		}
+		return s.outbox.Add(tx, queueName, request(job.ID))
+	})
 	if err != nil {
-		s.logger.Error("Failed to fetch song details", zap.Error(err))
-		d.Nack(false, true)
-		return
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 500 {
-		s.logger.Error("Failed to fetch song details", zap.String("status", resp.Status))
-		d.Nack(false, true)
-		return
-	} else if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-		s.logger.Error("Failed to fetch song details", zap.String("status", resp.Status))
+		return nil, err
 	}
+	s.outbox.Notify()
+	return job, nil
+}
 
-	var songDetail models.SongDetail
-	if err := json.NewDecoder(resp.Body).Decode(&songDetail); err != nil {
-		s.logger.Error("Failed to decode song details", zap.Error(err))
-		d.Reject(false)
-		return
+// failJob records err on the job, which stays pending while the message is going to be retried.
+func (s *SongService) failJob(queueName, jobID string, d amqp.Delivery, err error) error {
+	if s.ConsumerManager.WillRetry(queueName, d, err) {
+		s.jobs.MarkRetrying(jobID, err)
+	} else {
+		s.jobs.MarkFailed(jobID, err)
 	}
+	return err
+}
 
-	song.ReleaseDate = songDetail.ReleaseDate
-	song.Link = songDetail.Link
+// processOnce runs fn in a transaction that also records the message ID of d. If the ID has already been
+// recorded the delivery is a duplicate: fn is not run and processOnce returns false.
+func (s *SongService) processOnce(queueName string, d amqp.Delivery, fn func(tx *gorm.DB) error) (bool, error) {
+	processed := true
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if d.MessageId != "" {
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.ProcessedMessage{
+				MessageID:   d.MessageId,
+				Queue:       queueName,
+				ProcessedAt: time.Now(),
+			})
+			if result.Error != nil {
+				return fmt.Errorf("failed to record message: %w", result.Error)
+			}
+			if result.RowsAffected == 0 {
+				processed = false
+				return nil
+			}
+		}
+		return fn(tx)
+	})
+	return processed, err
+}
 
-	tx := s.db.Begin()
-	if err := tx.Create(&song).Error; err != nil {
-		tx.Rollback()
-		s.logger.Error("Failed to create song", zap.Error(err))
-		d.Nack(false, true)
-		return
+func (s *SongService) isProcessed(d amqp.Delivery) bool {
+	if d.MessageId == "" {
+		return false
 	}
+	var count int64
+	s.db.Model(&models.ProcessedMessage{}).Where("message_id = ?", d.MessageId).Count(&count)
+	return count > 0
+}
 
-	var verses []*models.Verse
-
-	for _, verse := range strings.Split(songDetail.Text, "\n\n") {
-		verses = append(verses, &models.Verse{SongID: song.ID, Text: verse})
+func (s *SongService) handleAddSong(d amqp.Delivery) error {
+	var addReq AddSongRequest
+	if err := json.Unmarshal(d.Body, &addReq); err != nil || addReq.Song == nil {
+		s.logger.Error("Failed to unmarshal add song request", zap.Error(err))
+		return permanent(fmt.Errorf("invalid add song request: %v", err))
+	}
+	song := *addReq.Song
+	if s.isProcessed(d) {
+		s.logger.Info("Skipping duplicate message", zap.String("queue", "add_song_queue"), zap.String("messageId", d.MessageId))
+		return nil
+	}
+	s.jobs.MarkRunning(addReq.JobID)
+
+	song.NormalizedKey = models.NormalizedKey(song.GroupName, song.SongName)
+	if existing, err := s.findByKey(s.db, song.NormalizedKey, 0); err != nil {
+		return s.failJob("add_song_queue", addReq.JobID, d, err)
+	} else if existing != nil {
+		s.logger.Info("Song already exists", zap.Uint("songId", existing.ID))
+		s.jobs.MarkSucceeded(addReq.JobID, existing.ID)
+		return nil
 	}
 
-	err = tx.Create(&verses).Error
+	songDetail, err := s.enricher.Enrich(context.Background(), song.GroupName, song.SongName)
 	if err != nil {
-		tx.Rollback()
-		s.logger.Error("Failed to create verses", zap.Error(err))
-		d.Nack(false, true)
-		return
+		s.logger.Error("Failed to fetch song details", zap.Error(err))
+		switch {
+		case errors.Is(err, ErrCircuitOpen):
+			err = deferred(err)
+		case errors.Is(err, ErrSongInfoNotFound), errors.Is(err, ErrSongInfoRejected):
+			err = permanent(err)
+		}
+		return s.failJob("add_song_queue", addReq.JobID, d, err)
 	}
 
-	if err := tx.Commit().Error; err != nil {
-		tx.Rollback()
-		s.logger.Error("Failed to commit transaction", zap.Error(err))
-		d.Nack(false, true)
-		return
+	enrichedAt := time.Now()
+	song.ReleaseDate = s.parseReleaseDate(songDetail.ReleaseDate)
+	song.Link = songDetail.Link
+	song.EnrichedAt = &enrichedAt
+
+	processed, err := s.processOnce("add_song_queue", d, func(tx *gorm.DB) error {
+		if err := tx.Create(&song).Error; err != nil {
+			return fmt.Errorf("failed to create song: %w", err)
+		}
+		if err := s.artists.CreditSong(tx, song.ID, append(models.GroupCredits(song.GroupName), song.Artists...)); err != nil {
+			return err
+		}
+
+		if err := s.replaceVerses(tx, song.ID, splitVerses(songDetail.Text)); err != nil {
+			return err
+		}
+		return s.revisions.Record(tx, song.ID, models.RevisionActionCreate, addReq.Origin)
+	})
+	if err != nil {
+		// A concurrent add of the same song wins the unique index; resolve to it.
+		if existing, _ := s.findByKey(s.db, song.NormalizedKey, 0); existing != nil {
+			s.logger.Info("Song already exists", zap.Uint("songId", existing.ID))
+			s.jobs.MarkSucceeded(addReq.JobID, existing.ID)
+			return nil
+		}
+		s.logger.Error("Failed to store song", zap.Error(err))
+		return s.failJob("add_song_queue", addReq.JobID, d, err)
+	}
+	if !processed {
+		s.logger.Info("Skipping duplicate message", zap.String("queue", "add_song_queue"), zap.String("messageId", d.MessageId))
+		return nil
 	}
 
-	d.Ack(false)
+	s.invalidate(strconv.FormatUint(uint64(song.ID), 10))
+	s.jobs.MarkSucceeded(addReq.JobID, song.ID)
+	return nil
 }
 
-func (s *SongService) handleUpdateSong(d amqp.Delivery) {
+func (s *SongService) handleUpdateSong(d amqp.Delivery) error {
 	var req UpdateSongRequest
 	if err := json.Unmarshal(d.Body, &req); err != nil {
 		s.logger.Error("Failed to unmarshal update song request", zap.Error(err))
-		d.Reject(false)
-		return
+		return permanent(err)
 	}
+	s.jobs.MarkRunning(req.JobID)
 
-	if err := s.UpdateSong(&req); err != nil {
+	processed, err := s.processOnce("update_song_queue", d, func(tx *gorm.DB) error {
+		return s.updateSong(tx, &req)
+	})
+	if err != nil {
 		s.logger.Error("Failed to update song", zap.Error(err))
-		d.Nack(false, true)
-		return
+		if errors.Is(err, ErrSongNotFound) || errors.Is(err, ErrDuplicateSong) {
+			err = permanent(err)
+		}
+		return s.failJob("update_song_queue", req.JobID, d, err)
+	}
+	if !processed {
+		s.logger.Info("Skipping duplicate message", zap.String("queue", "update_song_queue"), zap.String("messageId", d.MessageId))
+		return nil
 	}
 
-	d.Ack(false)
+	s.invalidate(req.SongID)
+	s.jobs.MarkSucceeded(req.JobID, parseSongID(req.SongID))
+	return nil
 }
 
-func (s *SongService) handleDeleteSong(d amqp.Delivery) {
+func (s *SongService) handleDeleteSong(d amqp.Delivery) error {
 	var req DeleteSongRequest
 	if err := json.Unmarshal(d.Body, &req); err != nil {
 		s.logger.Error("Failed to unmarshal delete song request", zap.Error(err))
-		d.Reject(false)
-		return
+		return permanent(err)
 	}
+	s.jobs.MarkRunning(req.JobID)
 
-	if err := s.DeleteSong(&req); err != nil {
+	processed, err := s.processOnce("delete_song_queue", d, func(tx *gorm.DB) error {
+		return s.deleteSong(tx, &req)
+	})
+	if err != nil {
 		s.logger.Error("Failed to delete song", zap.Error(err))
-		d.Nack(false, true)
-		return
+		if errors.Is(err, ErrSongNotFound) {
+			err = permanent(err)
+		}
+		return s.failJob("delete_song_queue", req.JobID, d, err)
+	}
+	if !processed {
+		s.logger.Info("Skipping duplicate message", zap.String("queue", "delete_song_queue"), zap.String("messageId", d.MessageId))
+		return nil
+	}
+
+	s.invalidate(req.SongId)
+	s.jobs.MarkSucceeded(req.JobID, parseSongID(req.SongId))
+	return nil
+}
+
+// invalidate drops the cached responses that a committed change to the song songId makes stale. Lists,
+// search results, albums and artists embed songs or their credits, so they go too.
+func (s *SongService) invalidate(songId string) {
+	s.cache.DeleteFromCache("song:" + songId)
+	s.cache.DeleteByPrefix("song:" + songId + ":")
+	for _, prefix := range []string{"songs:", "search:", "albums:", "artists:", "stats:"} {
+		s.cache.DeleteByPrefix(prefix)
+	}
+}
+
+func parseSongID(songId string) uint {
+	id, _ := strconv.ParseUint(songId, 10, 64)
+	return uint(id)
+}
+
+// findSong returns ErrSongNotFound unless the song songId exists and is not deleted.
+func findSong(tx *gorm.DB, songId string) error {
+	if err := tx.Where("id = ?", songId).First(&models.Song{}).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSongNotFound
+		}
+		return fmt.Errorf("failed to find song: %w", err)
 	}
+	return nil
+}
 
-	d.Ack(false)
+func songIDs(songs []*models.Song) []uint {
+	ids := make([]uint, 0, len(songs))
+	for _, song := range songs {
+		ids = append(ids, song.ID)
+	}
+	return ids
 }
 
 func (s *SongService) RegisterConsumers() {
 	s.ConsumerManager.RegisterHandler("add_song_queue", s.handleAddSong)
 	s.ConsumerManager.RegisterHandler("update_song_queue", s.handleUpdateSong)
 	s.ConsumerManager.RegisterHandler("delete_song_queue", s.handleDeleteSong)
+	s.ConsumerManager.RegisterHandler("refresh_song_queue", s.handleRefreshSong)
+	s.ConsumerManager.RegisterHandler("revert_song_queue", s.handleRevertSong)
 }
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"errors"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func createSong(t *testing.T, db *gorm.DB, group, name string) *models.Song {
+	t.Helper()
+	song := &models.Song{GroupName: group, SongName: name, NormalizedKey: models.NormalizedKey(group, name)}
+	if err := db.Create(song).Error; err != nil {
+		t.Fatalf("failed to create song: %v", err)
+	}
+	return song
+}
+
+func TestCheckDuplicate(t *testing.T) {
+	db := testDB(t)
+	service := &SongService{logger: zap.NewNop(), db: db}
+	muse := createSong(t, db, "Muse", "Supermassive Black Hole")
+	other := createSong(t, db, "Muse", "Uprising")
+	museID := strconv.FormatUint(uint64(muse.ID), 10)
+	otherID := strconv.FormatUint(uint64(other.ID), 10)
+
+	tests := []struct {
+		name   string
+		songID string
+		song   *models.Song
+		want   error
+		wantID uint
+	}{
+		{name: "new song", song: &models.Song{GroupName: "Muse", SongName: "Hysteria"}},
+		{name: "same song spelled differently", song: &models.Song{GroupName: "muse ", SongName: "supermassive  black hole!"}, want: ErrDuplicateSong, wantID: muse.ID},
+		{name: "patch keeping the name", songID: museID, song: &models.Song{Link: "https://example.com"}},
+		{name: "patch renaming to another song", songID: otherID, song: &models.Song{SongName: "Supermassive Black Hole"}, want: ErrDuplicateSong, wantID: muse.ID},
+		{name: "patch of a missing song", songID: "0", song: &models.Song{SongName: "Hysteria"}, want: ErrSongNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.CheckDuplicate(tt.songID, tt.song)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("CheckDuplicate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("CheckDuplicate() error = %v, want %v", err, tt.want)
+			}
+			var duplicate *DuplicateSongError
+			if errors.As(err, &duplicate) && duplicate.ID != tt.wantID {
+				t.Errorf("CheckDuplicate() reported song %d, want %d", duplicate.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestUpdateSongClearsEnrichedAtOnRename(t *testing.T) {
+	service, db := newTestSongService(t, &stubEnricher{name: "stub"})
+	song := createSong(t, db, "Muse", "Uprising")
+	songID := strconv.FormatUint(uint64(song.ID), 10)
+	enrichedAt := func() *time.Time {
+		t.Helper()
+		var current models.Song
+		if err := db.First(&current, song.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		return current.EnrichedAt
+	}
+	db.Model(song).Update("enriched_at", time.Now())
+
+	if err := service.UpdateSong(&UpdateSongRequest{SongID: songID, Song: &models.Song{Link: "https://example.com"}}); err != nil {
+		t.Fatalf("UpdateSong() error = %v", err)
+	}
+	if enrichedAt() == nil {
+		t.Error("enriched_at was cleared by an update keeping the name")
+	}
+
+	if err := service.UpdateSong(&UpdateSongRequest{SongID: songID, Song: &models.Song{SongName: "Resistance"}}); err != nil {
+		t.Fatalf("UpdateSong() error = %v", err)
+	}
+	if got := enrichedAt(); got != nil {
+		t.Errorf("enriched_at = %v after a rename, want it cleared", got)
+	}
+}
+
+func TestRequestDelete(t *testing.T) {
+	service, db := newTestSongService(t, &stubEnricher{name: "stub"})
+	song := createSong(t, db, "Muse", "Uprising")
+
+	job, err := service.RequestDelete(strconv.FormatUint(uint64(song.ID), 10), Origin{})
+	if err != nil {
+		t.Fatalf("RequestDelete() error = %v", err)
+	}
+	if job.Type != models.JobTypeDeleteSong {
+		t.Errorf("job type = %q, want %q", job.Type, models.JobTypeDeleteSong)
+	}
+	if _, err := service.RequestDelete("0", Origin{}); !errors.Is(err, ErrSongNotFound) {
+		t.Errorf("RequestDelete() of a missing song error = %v, want %v", err, ErrSongNotFound)
+	}
+}
+
+func TestGetSongText(t *testing.T) {
+	service, db := newTestSongService(t, &stubEnricher{name: "stub"})
+	song := createSong(t, db, "Muse", "Uprising")
+	for _, text := range []string{"first", "second", "third"} {
+		db.Create(&models.Verse{SongID: song.ID, Text: text})
+	}
+
+	verses, meta, err := service.GetSongText(&GetSongTextRequest{SongId: strconv.FormatUint(uint64(song.ID), 10), Page: "2", PageSize: "2"})
+	if err != nil {
+		t.Fatalf("GetSongText() error = %v", err)
+	}
+	if len(verses) != 1 || verses[0].Text != "third" || meta.Total != 3 {
+		t.Errorf("GetSongText() = %d verses of %d, want the third of 3", len(verses), meta.Total)
+	}
+
+	if _, _, err := service.GetSongText(&GetSongTextRequest{SongId: "0"}); !errors.Is(err, ErrSongNotFound) {
+		t.Errorf("GetSongText() of a missing song error = %v, want %v", err, ErrSongNotFound)
+	}
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/SZabrodskii/music-library/utils/filters"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidFacet = errors.New("invalid facet")
+
+// verseCountsJoin adds the number of verses of each song as verse_counts.verses, null for songs without lyrics.
+const verseCountsJoin = `LEFT JOIN (SELECT song_id, COUNT(*) AS verses FROM verses WHERE deleted_at IS NULL GROUP BY song_id) AS verse_counts
+    ON verse_counts.song_id = songs.id`
+
+const averageVersesColumn = "AVG(COALESCE(verse_counts.verses, 0))::float8 AS average_verses"
+
+// facetQueries bucket the songs of the subquery; each selects key, count and average_verses.
+var facetQueries = map[string]string{
+	"group": `SELECT songs.group_name AS key, COUNT(*) AS count, ` + averageVersesColumn + `
+FROM songs
+         ` + verseCountsJoin + `
+WHERE songs.id IN (?)
+GROUP BY songs.group_name
+ORDER BY count DESC, key`,
+	"year": `SELECT EXTRACT(YEAR FROM songs.release_date)::int::text AS key, COUNT(*) AS count, ` + averageVersesColumn + `
+FROM songs
+         ` + verseCountsJoin + `
+WHERE songs.id IN (?)
+GROUP BY key
+ORDER BY key NULLS LAST`,
+	// A song counts once in a genre even if it is also in one of its descendants.
+	"genre": `WITH RECURSIVE lineage AS (
+    SELECT id AS genre_id, id AS ancestor_id FROM genres
+    UNION ALL
+    SELECT lineage.genre_id, genres.parent_id
+    FROM lineage
+             JOIN genres ON genres.id = lineage.ancestor_id
+    WHERE genres.parent_id IS NOT NULL
+)
+SELECT genres.slug AS key, genres.name, COUNT(*) AS count, ` + averageVersesColumn + `
+FROM (SELECT DISTINCT lineage.ancestor_id, song_genres.song_id
+      FROM song_genres
+               JOIN lineage ON lineage.genre_id = song_genres.genre_id) AS song_lineage
+         JOIN genres ON genres.id = song_lineage.ancestor_id
+         JOIN songs ON songs.id = song_lineage.song_id
+         ` + verseCountsJoin + `
+WHERE songs.id IN (?)
+GROUP BY genres.id
+ORDER BY count DESC, key`,
+}
+
+const totalQuery = `SELECT COUNT(*), COALESCE(AVG(COALESCE(verse_counts.verses, 0)), 0)::float8
+FROM songs
+         ` + verseCountsJoin + `
+WHERE songs.id IN (?)`
+
+type StatsService struct {
+	logger *zap.Logger
+	db     *gorm.DB
+}
+
+func NewStatsService(logger *zap.Logger, db *gorm.DB) *StatsService {
+	return &StatsService{
+		logger: logger,
+		db:     db,
+	}
+}
+
+type GetFacetStatsRequest struct {
+	// By is group, year or genre.
+	By      string            `json:"by"`
+	Filters []*filters.Filter `json:"filters"`
+}
+
+// GetFacetStats counts the songs matching Filters per bucket of By, with their average number of verses.
+func (s *StatsService) GetFacetStats(req *GetFacetStatsRequest) (*models.FacetStats, error) {
+	query, ok := facetQueries[req.By]
+	if !ok {
+		return nil, fmt.Errorf("%w: by must be group, year or genre, not %q", ErrInvalidFacet, req.By)
+	}
+	songs := filters.Apply(s.db.Model(&models.Song{}), req.Filters).Select("songs.id")
+
+	stats := &models.FacetStats{By: req.By, Buckets: make([]*models.Bucket, 0)}
+	if err := s.db.Raw(totalQuery, songs).Row().Scan(&stats.Total, &stats.AverageVerses); err != nil {
+		return nil, fmt.Errorf("failed to count songs: %w", err)
+	}
+	if err := s.db.Raw(query, songs).Scan(&stats.Buckets).Error; err != nil {
+		return nil, fmt.Errorf("failed to count songs by %s: %w", req.By, err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"github.com/SZabrodskii/music-library/utils/pagination"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net/url"
+)
+
+var ErrInvalidTag = errors.New("invalid tag")
+
+// tagSongCountColumn counts the songs that are not deleted of each tag.
+const tagSongCountColumn = `(SELECT COUNT(*)
+    FROM song_tags
+             JOIN songs ON songs.id = song_tags.song_id AND songs.deleted_at IS NULL
+    WHERE song_tags.tag_id = tags.id) AS song_count`
+
+// tagFacetsQuery counts the songs of the subquery per tag, keeping the most used tags.
+const tagFacetsQuery = `SELECT tags.slug, tags.name, COUNT(*) AS count
+FROM song_tags
+         JOIN tags ON tags.id = song_tags.tag_id
+WHERE song_tags.song_id IN (?)
+GROUP BY tags.id
+ORDER BY count DESC, tags.slug
+LIMIT ?`
+
+// maxTagFacets bounds the tag facets of a song list, since anyone can add tags.
+const maxTagFacets = 20
+
+type TagService struct {
+	logger *zap.Logger
+	db     *gorm.DB
+}
+
+func NewTagService(logger *zap.Logger, db *gorm.DB) *TagService {
+	return &TagService{
+		logger: logger,
+		db:     db,
+	}
+}
+
+type GetTagsRequest struct {
+	Query    string `json:"q"`
+	Page     string `json:"page"`
+	PageSize string `json:"pageSize"`
+}
+
+// GetTags lists tags by slug, optionally only those whose slug contains the slug of Query.
+func (s *TagService) GetTags(req *GetTagsRequest) ([]*models.Tag, *pagination.Pagination, error) {
+	tags := make([]*models.Tag, 0)
+	query := s.db.Model(&models.Tag{})
+	if slug := models.Slug(req.Query); slug != "" {
+		// Slugs only hold letters, digits and dashes, so there is nothing to escape.
+		query = query.Where("tags.slug LIKE ?", "%"+slug+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, nil, err
+	}
+
+	page, pageSize := pagination.Normalize(req.Page, req.PageSize)
+	err := query.Select("tags.*, " + tagSongCountColumn).
+		Order("tags.slug").
+		Offset(pagination.Offset(page, pageSize)).
+		Limit(pageSize).
+		Find(&tags).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	meta := pagination.New(total, page, pageSize, url.Values{"q": {req.Query}})
+	return tags, &meta, nil
+}
+
+// AttachTag tags the song songID with name, creating the tag if needed. Attaching it twice is a no-op.
+func (s *TagService) AttachTag(songID, name string) error {
+	slug := models.Slug(name)
+	if slug == "" {
+		return fmt.Errorf("%w: %q has no letters or digits", ErrInvalidTag, name)
+	}
+	if err := findSong(s.db, songID); err != nil {
+		return err
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		tag := &models.Tag{Name: name, Slug: slug}
+		err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "slug"}}, DoNothing: true}).
+			Create(tag).Error
+		if err != nil {
+			return fmt.Errorf("failed to create tag: %w", err)
+		}
+		if err := tx.Where("slug = ?", slug).First(tag).Error; err != nil {
+			return fmt.Errorf("failed to find tag: %w", err)
+		}
+		err = tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.SongTag{SongID: parseSongID(songID), TagID: tag.ID}).Error
+		if err != nil {
+			return fmt.Errorf("failed to attach tag: %w", err)
+		}
+		return nil
+	})
+}
+
+// DetachTag removes the tag name from the song songID, if it has it. The tag itself is kept.
+func (s *TagService) DetachTag(songID, name string) error {
+	if err := findSong(s.db, songID); err != nil {
+		return err
+	}
+	err := s.db.Where("song_id = ? AND tag_id IN (?)", songID, s.db.Model(&models.Tag{}).Select("id").Where("slug = ?", models.Slug(name))).
+		Delete(&models.SongTag{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to detach tag: %w", err)
+	}
+	return nil
+}
+
+// LoadTags fills the Tags of songs, ordered by slug.
+func (s *TagService) LoadTags(tx *gorm.DB, songs []*models.Song) error {
+	if len(songs) == 0 {
+		return nil
+	}
+	var rows []struct {
+		SongID uint
+		Slug   string
+		Name   string
+	}
+	err := tx.Table("song_tags").
+		Select("song_tags.song_id, tags.slug, tags.name").
+		Joins("JOIN tags ON tags.id = song_tags.tag_id").
+		Where("song_tags.song_id IN ?", songIDs(songs)).
+		Order("tags.slug").
+		Scan(&rows).Error
+	if err != nil {
+		return fmt.Errorf("failed to load tags: %w", err)
+	}
+
+	bySong := make(map[uint][]*models.Label, len(songs))
+	for _, row := range rows {
+		bySong[row.SongID] = append(bySong[row.SongID], &models.Label{Slug: row.Slug, Name: row.Name})
+	}
+	for _, song := range songs {
+		song.Tags = bySong[song.ID]
+	}
+	return nil
+}
+
+// Facets counts the songs selected by the subquery songs, which must select song IDs, per tag.
+func (s *TagService) Facets(songs *gorm.DB) ([]*models.Facet, error) {
+	facets := make([]*models.Facet, 0)
+	if err := s.db.Raw(tagFacetsQuery, songs, maxTagFacets).Scan(&facets).Error; err != nil {
+		return nil, fmt.Errorf("failed to count tags: %w", err)
+	}
+	return facets, nil
+}
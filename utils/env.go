@@ -7,7 +7,7 @@ import (
 	"strings"
 )
 
-func GetEnv[T comparable](key string, defaultValue T) T {
+func GetEnv[T any](key string, defaultValue T) T {
 	value, exists := os.LookupEnv(key)
 	if !exists {
 		return defaultValue
@@ -23,7 +23,7 @@ func GetEnv[T comparable](key string, defaultValue T) T {
 		slice := reflect.MakeSlice(reflect.SliceOf(sliceType), len(valueSlice), len(valueSlice))
 		for i, v := range valueSlice {
 			sliceValue := convert(v, sliceType)
-			slice.Index(i).Set(reflect.ValueOf(sliceValue))
+			slice.Index(i).Set(sliceValue)
 		}
 		return slice.Interface().(T)
 	default:
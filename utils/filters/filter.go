@@ -0,0 +1,257 @@
+package filters
+
+import (
+	"errors"
+	"fmt"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"gorm.io/gorm"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidFilter = errors.New("invalid filter")
+
+type Operator string
+
+const (
+	OperatorEq       Operator = "eq"
+	OperatorNeq      Operator = "neq"
+	OperatorGt       Operator = "gt"
+	OperatorGte      Operator = "gte"
+	OperatorLt       Operator = "lt"
+	OperatorLte      Operator = "lte"
+	OperatorContains Operator = "contains"
+	OperatorIn       Operator = "in"
+	// OperatorAll matches songs that carry every listed value, where OperatorIn matches any of them.
+	OperatorAll Operator = "all"
+)
+
+type fieldKind int
+
+const (
+	kindString fieldKind = iota
+	kindNumber
+	kindDate
+	kindArtist
+	kindSlug
+)
+
+type field struct {
+	column    string
+	kind      fieldKind
+	operators []Operator
+	// exists, if set, is a subquery template the condition on column is placed in.
+	exists string
+}
+
+var (
+	comparisonOperators = []Operator{OperatorEq, OperatorNeq, OperatorGt, OperatorGte, OperatorLt, OperatorLte, OperatorIn}
+	textOperators       = []Operator{OperatorEq, OperatorNeq, OperatorContains, OperatorIn}
+	artistOperators     = []Operator{OperatorEq, OperatorContains, OperatorIn}
+	labelOperators      = []Operator{OperatorEq, OperatorIn, OperatorAll}
+)
+
+const artistExists = `EXISTS (SELECT 1 FROM song_artists JOIN artists ON artists.id = song_artists.artist_id
+    WHERE song_artists.song_id = songs.id AND %s)`
+
+// genreExists matches the genres in the condition and all their descendants.
+const genreExists = `EXISTS (WITH RECURSIVE matched AS (
+        SELECT genres.id FROM genres WHERE %s
+        UNION
+        SELECT genres.id FROM genres JOIN matched ON genres.parent_id = matched.id
+    )
+    SELECT 1 FROM song_genres JOIN matched ON matched.id = song_genres.genre_id
+    WHERE song_genres.song_id = songs.id)`
+
+const tagExists = `EXISTS (SELECT 1 FROM song_tags JOIN tags ON tags.id = song_tags.tag_id
+    WHERE song_tags.song_id = songs.id AND %s)`
+
+var songFields = map[string]field{
+	"id":          {column: "songs.id", kind: kindNumber, operators: comparisonOperators},
+	"group":       {column: "songs.group_name", kind: kindString, operators: textOperators},
+	"song":        {column: "songs.song_name", kind: kindString, operators: textOperators},
+	"releaseDate": {column: "songs.release_date", kind: kindDate, operators: comparisonOperators},
+	"link":        {column: "songs.link", kind: kindString, operators: textOperators},
+	"artist":      {column: "artists.normalized_name", kind: kindArtist, operators: artistOperators, exists: artistExists},
+	"genre":       {column: "genres.slug", kind: kindSlug, operators: labelOperators, exists: genreExists},
+	"tag":         {column: "tags.slug", kind: kindSlug, operators: labelOperators, exists: tagExists},
+}
+
+// Filter is a single validated condition of the form field:operator:value.
+type Filter struct {
+	Field    string
+	Operator Operator
+	Value    string
+}
+
+func (f *Filter) String() string {
+	return f.Field + ":" + string(f.Operator) + ":" + f.Value
+}
+
+// Parse validates a raw filter such as "group:eq:Muse" against the whitelisted song fields.
+func Parse(raw string) (*Filter, error) {
+	parts := strings.SplitN(raw, ":", 3)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: %q must have the form field:operator:value", ErrInvalidFilter, raw)
+	}
+
+	filter := &Filter{Field: parts[0], Operator: Operator(parts[1]), Value: parts[2]}
+	f, ok := songFields[filter.Field]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidFilter, filter.Field)
+	}
+	if !supports(f, filter.Operator) {
+		return nil, fmt.Errorf("%w: unknown operator %q for field %q", ErrInvalidFilter, filter.Operator, filter.Field)
+	}
+	values := filter.values()
+	for i, value := range values {
+		normalized, err := normalizeValue(f.kind, value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %q: %v", ErrInvalidFilter, filter.Field, err)
+		}
+		values[i] = normalized
+	}
+	filter.Value = strings.Join(values, ",")
+
+	return filter, nil
+}
+
+// ParseReleased turns the releasedAfter and releasedBefore query parameters into inclusive release date
+// filters. Blank parameters are ignored.
+func ParseReleased(after, before string) ([]*Filter, error) {
+	var filters []*Filter
+	for _, bound := range []struct {
+		operator Operator
+		value    string
+	}{{OperatorGte, after}, {OperatorLte, before}} {
+		if bound.value == "" {
+			continue
+		}
+		filter, err := Parse("releaseDate:" + string(bound.operator) + ":" + bound.value)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+// ParseQuery parses the filters and the release date bounds of a song list request.
+func ParseQuery(raw []string, releasedAfter, releasedBefore string) ([]*Filter, error) {
+	filters, err := ParseAll(raw)
+	if err != nil {
+		return nil, err
+	}
+	released, err := ParseReleased(releasedAfter, releasedBefore)
+	if err != nil {
+		return nil, err
+	}
+	return append(filters, released...), nil
+}
+
+func ParseAll(raw []string) ([]*Filter, error) {
+	filters := make([]*Filter, 0, len(raw))
+	for _, r := range raw {
+		filter, err := Parse(r)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+func Strings(filters []*Filter) []string {
+	raw := make([]string, 0, len(filters))
+	for _, filter := range filters {
+		raw = append(raw, filter.String())
+	}
+	return raw
+}
+
+// Apply adds the filters to the query using bound parameters only.
+func Apply(query *gorm.DB, filters []*Filter) *gorm.DB {
+	for _, filter := range filters {
+		f := songFields[filter.Field]
+		var condition string
+		var value interface{} = filter.Value
+		switch filter.Operator {
+		case OperatorEq:
+			condition = f.column + " = ?"
+		case OperatorNeq:
+			condition = f.column + " <> ?"
+		case OperatorGt:
+			condition = f.column + " > ?"
+		case OperatorGte:
+			condition = f.column + " >= ?"
+		case OperatorLt:
+			condition = f.column + " < ?"
+		case OperatorLte:
+			condition = f.column + " <= ?"
+		case OperatorContains:
+			condition, value = f.column+" ILIKE ? ESCAPE '\\'", "%"+escapeLike(filter.Value)+"%"
+		case OperatorIn:
+			condition, value = f.column+" IN ?", filter.values()
+		case OperatorAll:
+			for _, v := range filter.values() {
+				query = query.Where(fmt.Sprintf(f.exists, f.column+" = ?"), v)
+			}
+			continue
+		}
+		if f.exists != "" {
+			condition = fmt.Sprintf(f.exists, condition)
+		}
+		query = query.Where(condition, value)
+	}
+	return query
+}
+
+func (f *Filter) values() []string {
+	if f.Operator == OperatorIn || f.Operator == OperatorAll {
+		return strings.Split(f.Value, ",")
+	}
+	return []string{f.Value}
+}
+
+func supports(f field, operator Operator) bool {
+	for _, op := range f.operators {
+		if op == operator {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeValue validates value and returns it in the form stored in the database; dates in any known
+// format become YYYY-MM-DD.
+func normalizeValue(kind fieldKind, value string) (string, error) {
+	switch kind {
+	case kindNumber:
+		if _, err := strconv.ParseUint(value, 10, 64); err != nil {
+			return "", fmt.Errorf("%q is not a number", value)
+		}
+	case kindDate:
+		date, err := models.ParseDate(value)
+		if err != nil || date.IsZero() {
+			return "", fmt.Errorf("%q is not a date in %s or DD.MM.YYYY format", value, models.DateLayout)
+		}
+		return date.String(), nil
+	case kindArtist:
+		name := models.NormalizedArtistName(value)
+		if name == "" {
+			return "", fmt.Errorf("%q is not an artist name", value)
+		}
+		return name, nil
+	case kindSlug:
+		slug := models.Slug(value)
+		if slug == "" {
+			return "", fmt.Errorf("%q is not a genre or tag name", value)
+		}
+		return slug, nil
+	}
+	return value, nil
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(value)
+}
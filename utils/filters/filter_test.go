@@ -0,0 +1,235 @@
+package filters
+
+import (
+	"errors"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// whereClause builds a song query with apply without running it and returns its WHERE clause and arguments.
+func whereClause(t *testing.T, apply func(query *gorm.DB) *gorm.DB) (string, []interface{}) {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("failed to open dry run database: %v", err)
+	}
+	stmt := apply(db.Model(&models.Song{})).Find(&[]models.Song{}).Statement
+	sql := stmt.SQL.String()
+	_, where, ok := strings.Cut(sql, " WHERE ")
+	if !ok {
+		t.Fatalf("query has no WHERE clause: %s", sql)
+	}
+	return strings.TrimSuffix(where, ` AND "songs"."deleted_at" IS NULL`), stmt.Vars
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    *Filter
+		wantErr bool
+	}{
+		{raw: "group:eq:Muse", want: &Filter{Field: "group", Operator: OperatorEq, Value: "Muse"}},
+		{raw: "song:contains:a:b", want: &Filter{Field: "song", Operator: OperatorContains, Value: "a:b"}},
+		{raw: "id:in:1,2,3", want: &Filter{Field: "id", Operator: OperatorIn, Value: "1,2,3"}},
+		{raw: "releaseDate:gte:2006-07-16", want: &Filter{Field: "releaseDate", Operator: OperatorGte, Value: "2006-07-16"}},
+		{raw: "releaseDate:gte:16.07.2006", want: &Filter{Field: "releaseDate", Operator: OperatorGte, Value: "2006-07-16"}},
+		{raw: "releaseDate:lt:2006", want: &Filter{Field: "releaseDate", Operator: OperatorLt, Value: "2006-01-01"}},
+		{raw: "artist:eq:  MUSE! ", want: &Filter{Field: "artist", Operator: OperatorEq, Value: "muse"}},
+		{raw: "genre:in:Alternative Rock,Pop", want: &Filter{Field: "genre", Operator: OperatorIn, Value: "alternative-rock,pop"}},
+		{raw: "tag:all:Workout,Road Trip", want: &Filter{Field: "tag", Operator: OperatorAll, Value: "workout,road-trip"}},
+		{raw: "group:eq", wantErr: true},
+		{raw: "name:eq:Muse", wantErr: true},
+		{raw: "group:gt:Muse", wantErr: true},
+		{raw: "id:contains:1", wantErr: true},
+		{raw: "id:eq:ten", wantErr: true},
+		{raw: "id:in:1,x", wantErr: true},
+		{raw: "releaseDate:eq:someday", wantErr: true},
+		{raw: "releaseDate:eq:2006-02-30", wantErr: true},
+		{raw: "artist:neq:Muse", wantErr: true},
+		{raw: "artist:eq:!!", wantErr: true},
+		{raw: "tag:eq:", wantErr: true},
+		{raw: "group:all:Muse", wantErr: true},
+		{raw: "group:eq:x' OR '1'='1", want: &Filter{Field: "group", Operator: OperatorEq, Value: "x' OR '1'='1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := Parse(tt.raw)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidFilter) {
+					t.Fatalf("Parse(%q) error = %v, want ErrInvalidFilter", tt.raw, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.raw, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseReleased(t *testing.T) {
+	tests := []struct {
+		name    string
+		after   string
+		before  string
+		want    []string
+		wantErr bool
+	}{
+		{name: "none", want: []string{}},
+		{name: "after", after: "2006", want: []string{"releaseDate:gte:2006-01-01"}},
+		{name: "both", after: "16.07.2006", before: "2009-12-31", want: []string{"releaseDate:gte:2006-07-16", "releaseDate:lte:2009-12-31"}},
+		{name: "invalid", before: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseReleased(tt.after, tt.before)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidFilter) {
+					t.Fatalf("ParseReleased() error = %v, want ErrInvalidFilter", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseReleased() error = %v", err)
+			}
+			if !reflect.DeepEqual(Strings(got), tt.want) {
+				t.Errorf("ParseReleased() = %v, want %v", Strings(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name           string
+		raw            []string
+		releasedAfter  string
+		releasedBefore string
+		want           []string
+		wantErr        bool
+	}{
+		{name: "empty", want: []string{}},
+		{name: "filters only", raw: []string{"group:eq:Muse"}, want: []string{"group:eq:Muse"}},
+		{
+			name:           "release bounds",
+			raw:            []string{"group:eq:Muse"},
+			releasedAfter:  "2006",
+			releasedBefore: "31.12.2009",
+			want:           []string{"group:eq:Muse", "releaseDate:gte:2006-01-01", "releaseDate:lte:2009-12-31"},
+		},
+		{name: "invalid filter", raw: []string{"group"}, wantErr: true},
+		{name: "invalid bound", releasedBefore: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseQuery(tt.raw, tt.releasedAfter, tt.releasedBefore)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidFilter) {
+					t.Fatalf("ParseQuery() error = %v, want ErrInvalidFilter", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseQuery() error = %v", err)
+			}
+			if !reflect.DeepEqual(Strings(got), tt.want) {
+				t.Errorf("ParseQuery() = %v, want %v", Strings(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       []string
+		wantWhere string
+		wantVars  []interface{}
+	}{
+		{
+			name:      "comparisons",
+			raw:       []string{"group:eq:Muse", "song:neq:Uprising", "id:gt:1", "id:lte:9"},
+			wantWhere: "songs.group_name = $1 AND songs.song_name <> $2 AND songs.id > $3 AND songs.id <= $4",
+			wantVars:  []interface{}{"Muse", "Uprising", "1", "9"},
+		},
+		{
+			name:      "contains escapes wildcards",
+			raw:       []string{"song:contains:100%_a\\b"},
+			wantWhere: `songs.song_name ILIKE $1 ESCAPE '\'`,
+			wantVars:  []interface{}{`%100\%\_a\\b%`},
+		},
+		{
+			name:      "in",
+			raw:       []string{"id:in:1,2"},
+			wantWhere: "songs.id IN ($1,$2)",
+			wantVars:  []interface{}{"1", "2"},
+		},
+		{
+			name:      "release date",
+			raw:       []string{"releaseDate:gte:16.07.2006"},
+			wantWhere: "songs.release_date >= $1",
+			wantVars:  []interface{}{"2006-07-16"},
+		},
+		{
+			name:      "artist",
+			raw:       []string{"artist:eq:Muse"},
+			wantWhere: "(EXISTS (SELECT 1 FROM song_artists JOIN artists ON artists.id = song_artists.artist_id\n    WHERE song_artists.song_id = songs.id AND artists.normalized_name = $1))",
+			wantVars:  []interface{}{"muse"},
+		},
+		{
+			name: "all tags",
+			raw:  []string{"tag:all:workout,road trip"},
+			wantWhere: "(EXISTS (SELECT 1 FROM song_tags JOIN tags ON tags.id = song_tags.tag_id\n    WHERE song_tags.song_id = songs.id AND tags.slug = $1))" +
+				" AND (EXISTS (SELECT 1 FROM song_tags JOIN tags ON tags.id = song_tags.tag_id\n    WHERE song_tags.song_id = songs.id AND tags.slug = $2))",
+			wantVars: []interface{}{"workout", "road-trip"},
+		},
+		{
+			name:      "values are bound",
+			raw:       []string{"group:eq:x' OR '1'='1"},
+			wantWhere: "songs.group_name = $1",
+			wantVars:  []interface{}{"x' OR '1'='1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters, err := ParseAll(tt.raw)
+			if err != nil {
+				t.Fatalf("ParseAll(%v) error = %v", tt.raw, err)
+			}
+			where, vars := whereClause(t, func(query *gorm.DB) *gorm.DB {
+				return Apply(query, filters)
+			})
+			if where != tt.wantWhere {
+				t.Errorf("Apply() WHERE = %q, want %q", where, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(vars, tt.wantVars) {
+				t.Errorf("Apply() vars = %v, want %v", vars, tt.wantVars)
+			}
+		})
+	}
+}
+
+func TestApplyGenreMatchesDescendants(t *testing.T) {
+	filters, err := ParseAll([]string{"genre:in:rock,pop"})
+	if err != nil {
+		t.Fatalf("ParseAll() error = %v", err)
+	}
+	where, vars := whereClause(t, func(query *gorm.DB) *gorm.DB {
+		return Apply(query, filters)
+	})
+	for _, part := range []string{"WITH RECURSIVE matched", "genres.slug IN ($1,$2)", "genres.parent_id = matched.id"} {
+		if !strings.Contains(where, part) {
+			t.Errorf("Apply() WHERE = %q, want it to contain %q", where, part)
+		}
+	}
+	if want := []interface{}{"rock", "pop"}; !reflect.DeepEqual(vars, want) {
+		t.Errorf("Apply() vars = %v, want %v", vars, want)
+	}
+}
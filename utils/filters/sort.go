@@ -0,0 +1,135 @@
+package filters
+
+import (
+	"errors"
+	"fmt"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"gorm.io/gorm"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidSort = errors.New("invalid sort")
+
+type sortField struct {
+	expression string
+	value      func(song *models.Song) string
+}
+
+var songSortFields = map[string]sortField{
+	"id": {
+		expression: "songs.id",
+		value:      func(song *models.Song) string { return strconv.FormatUint(uint64(song.ID), 10) },
+	},
+	"group": {
+		expression: "songs.group_name",
+		value:      func(song *models.Song) string { return song.GroupName },
+	},
+	"song": {
+		expression: "songs.song_name",
+		value:      func(song *models.Song) string { return song.SongName },
+	},
+	"releaseDate": {
+		expression: "COALESCE(songs.release_date, DATE '0001-01-01')",
+		value: func(song *models.Song) string {
+			if song.ReleaseDate.IsZero() {
+				return "0001-01-01"
+			}
+			return song.ReleaseDate.String()
+		},
+	},
+	"createdAt": {
+		expression: "songs.created_at",
+		value:      func(song *models.Song) string { return song.CreatedAt.Format(time.RFC3339Nano) },
+	},
+}
+
+// SortKey is a single whitelisted sort field; a leading "-" in the raw form means descending.
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+func (k SortKey) String() string {
+	if k.Desc {
+		return "-" + k.Field
+	}
+	return k.Field
+}
+
+// ParseSort parses a comma-separated list such as "group,-releaseDate". The result always ends
+// with the song ID so that the order is total, which keyset pagination relies on.
+func ParseSort(raw string) ([]SortKey, error) {
+	keys := make([]SortKey, 0)
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key := SortKey{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if _, ok := songSortFields[key.Field]; !ok {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidSort, key.Field)
+		}
+		if seen[key.Field] {
+			return nil, fmt.Errorf("%w: field %q is used more than once", ErrInvalidSort, key.Field)
+		}
+		seen[key.Field] = true
+		keys = append(keys, key)
+	}
+	if !seen["id"] {
+		keys = append(keys, SortKey{Field: "id"})
+	}
+	return keys, nil
+}
+
+func SortString(keys []SortKey) string {
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, key.String())
+	}
+	return strings.Join(parts, ",")
+}
+
+func ApplySort(query *gorm.DB, keys []SortKey) *gorm.DB {
+	for _, key := range keys {
+		expression := songSortFields[key.Field].expression
+		if key.Desc {
+			expression += " DESC"
+		}
+		query = query.Order(expression)
+	}
+	return query
+}
+
+// SortValues returns the values of the sort keys for song, in the form stored in a cursor.
+func SortValues(keys []SortKey, song *models.Song) []string {
+	values := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, songSortFields[key.Field].value(song))
+	}
+	return values
+}
+
+// ApplyAfter restricts the query to rows that come after values in the order given by keys:
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ..., with < for descending keys.
+func ApplyAfter(query *gorm.DB, keys []SortKey, values []string) *gorm.DB {
+	conditions := make([]string, 0, len(keys))
+	args := make([]interface{}, 0)
+	for i, key := range keys {
+		parts := make([]string, 0, i+1)
+		for j, previous := range keys[:i] {
+			parts = append(parts, songSortFields[previous.Field].expression+" = ?")
+			args = append(args, values[j])
+		}
+		operator := " > ?"
+		if key.Desc {
+			operator = " < ?"
+		}
+		parts = append(parts, songSortFields[key.Field].expression+operator)
+		args = append(args, values[i])
+		conditions = append(conditions, "("+strings.Join(parts, " AND ")+")")
+	}
+	return query.Where("("+strings.Join(conditions, " OR ")+")", args...)
+}
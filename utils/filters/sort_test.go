@@ -0,0 +1,95 @@
+package filters
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"reflect"
+	"testing"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    []SortKey
+		wantErr bool
+	}{
+		{raw: "", want: []SortKey{{Field: "id"}}},
+		{raw: "group,-releaseDate", want: []SortKey{{Field: "group"}, {Field: "releaseDate", Desc: true}, {Field: "id"}}},
+		{raw: " song , ,createdAt", want: []SortKey{{Field: "song"}, {Field: "createdAt"}, {Field: "id"}}},
+		{raw: "-id,group", want: []SortKey{{Field: "id", Desc: true}, {Field: "group"}}},
+		{raw: "name", wantErr: true},
+		{raw: "group,-group", wantErr: true},
+		{raw: "--group", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := ParseSort(tt.raw)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSort) {
+					t.Fatalf("ParseSort(%q) error = %v, want ErrInvalidSort", tt.raw, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSort(%q) error = %v", tt.raw, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSort(%q) = %v, want %v", tt.raw, got, tt.want)
+			}
+			if again, err := ParseSort(SortString(got)); err != nil || !reflect.DeepEqual(again, got) {
+				t.Errorf("ParseSort(SortString(%v)) = %v, %v, want the same keys", got, again, err)
+			}
+		})
+	}
+}
+
+func TestApplyAfter(t *testing.T) {
+	tests := []struct {
+		name      string
+		sort      string
+		values    []string
+		wantWhere string
+		wantVars  []interface{}
+	}{
+		{
+			name:      "id only",
+			sort:      "",
+			values:    []string{"7"},
+			wantWhere: "((songs.id > $1))",
+			wantVars:  []interface{}{"7"},
+		},
+		{
+			name:      "descending id",
+			sort:      "-id",
+			values:    []string{"7"},
+			wantWhere: "((songs.id < $1))",
+			wantVars:  []interface{}{"7"},
+		},
+		{
+			name:   "several keys",
+			sort:   "group,-releaseDate",
+			values: []string{"Muse", "2006-07-03", "7"},
+			wantWhere: "(((songs.group_name > $1)" +
+				" OR (songs.group_name = $2 AND COALESCE(songs.release_date, DATE '0001-01-01') < $3)" +
+				" OR (songs.group_name = $4 AND COALESCE(songs.release_date, DATE '0001-01-01') = $5 AND songs.id > $6)))",
+			wantVars: []interface{}{"Muse", "Muse", "2006-07-03", "Muse", "2006-07-03", "7"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := ParseSort(tt.sort)
+			if err != nil {
+				t.Fatalf("ParseSort(%q) error = %v", tt.sort, err)
+			}
+			where, vars := whereClause(t, func(query *gorm.DB) *gorm.DB {
+				return ApplyAfter(query, keys, tt.values)
+			})
+			if where != tt.wantWhere {
+				t.Errorf("ApplyAfter() WHERE = %q, want %q", where, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(vars, tt.wantVars) {
+				t.Errorf("ApplyAfter() vars = %v, want %v", vars, tt.wantVars)
+			}
+		})
+	}
+}
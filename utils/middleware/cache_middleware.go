@@ -25,9 +25,13 @@ func CacheMiddleware(cache *providers.CacheProvider) gin.HandlerFunc {
 			c.Next()
 			return
 		}
-		cacheKey := generateCacheKey(c)
+		cacheKey, ok := generateCacheKey(c)
+		if !ok {
+			c.Next()
+			return
+		}
 		if val, ok := cache.GetFromCache(cacheKey); ok {
-			c.JSON(http.StatusOK, val)
+			c.Data(http.StatusOK, "application/json; charset=utf-8", val)
 			c.Abort()
 			return
 		}
@@ -42,11 +46,51 @@ func CacheMiddleware(cache *providers.CacheProvider) gin.HandlerFunc {
 	}
 }
 
-func generateCacheKey(c *gin.Context) string {
+// generateCacheKey builds the key of a cached response from ':'-separated parts. Every namespace ends in
+// ':', so invalidating one by prefix never reaches into another.
+func generateCacheKey(c *gin.Context) (string, bool) {
+	if strings.Contains(c.FullPath(), "/jobs/") || strings.HasSuffix(c.FullPath(), "/health") {
+		return "", false
+	}
 	query := c.Request.URL.Query()
 	page := query.Get("page")
 	pageSize := query.Get("pageSize")
+	if strings.HasSuffix(c.FullPath(), "/search") {
+		return "search:lyrics:" + query.Get("q") + ":" + page + ":" + pageSize, true
+	}
+	if strings.HasSuffix(c.FullPath(), "/search/songs") {
+		return "search:songs:" + query.Get("q") + ":" + page + ":" + pageSize, true
+	}
+	if strings.Contains(c.FullPath(), "/artists") {
+		return "artists:" + c.Param("artistId") + ":" + query.Get("q") + ":" + page + ":" + pageSize, true
+	}
+	if strings.HasSuffix(c.FullPath(), "/stats/facets") {
+		return "stats:facets:" + query.Get("by") + ":" + strings.Join(query["filters"], "_") + ":" +
+			query.Get("releasedAfter") + ":" + query.Get("releasedBefore"), true
+	}
+	if strings.HasSuffix(c.FullPath(), "/genres") {
+		return "genres:", true
+	}
+	if strings.HasSuffix(c.FullPath(), "/tags") {
+		return "tags:" + query.Get("q") + ":" + page + ":" + pageSize, true
+	}
+	if strings.Contains(c.FullPath(), "/albums") {
+		return "albums:" + c.Param("albumId") + ":" + page + ":" + pageSize, true
+	}
+	if songId := c.Param("songId"); songId != "" {
+		if strings.HasSuffix(c.FullPath(), "/text") {
+			return "song:" + songId + ":text:" + page + ":" + pageSize, true
+		}
+		if strings.HasSuffix(c.FullPath(), "/history") {
+			return "song:" + songId + ":history:" + page + ":" + pageSize, true
+		}
+		return "song:" + songId, true
+	}
+	cursor := query.Get("cursor")
+	limit := query.Get("limit")
+	sort := query.Get("sort")
 	filters := query["filters"]
 	filterString := strings.Join(filters, "_")
-	return "songs_" + page + "_" + pageSize + "_" + filterString
+	released := query.Get("releasedAfter") + ":" + query.Get("releasedBefore")
+	return "songs:" + page + ":" + pageSize + ":" + cursor + ":" + limit + ":" + sort + ":" + filterString + ":" + released, true
 }
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGenerateCacheKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	for _, path := range []string{
+		"/songs", "/songs/:songId", "/songs/:songId/text", "/songs/:songId/history",
+		"/search", "/search/songs", "/artists", "/stats/facets", "/genres", "/tags", "/albums/:albumId",
+	} {
+		router.GET(path, func(c *gin.Context) {
+			key, _ := generateCacheKey(c)
+			c.String(http.StatusOK, key)
+		})
+	}
+	key := func(target string) string {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w.Body.String()
+	}
+
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{target: "/songs/1", want: "song:1"},
+		{target: "/songs/1/text?page=2&pageSize=5", want: "song:1:text:2:5"},
+		{target: "/songs/1/history?page=1", want: "song:1:history:1:"},
+		{target: "/search?q=love&page=1&pageSize=10", want: "search:lyrics:love:1:10"},
+		{target: "/search/songs?q=love&page=1&pageSize=10", want: "search:songs:love:1:10"},
+		{target: "/albums/3", want: "albums:3::"},
+	}
+	for _, tt := range tests {
+		if got := key(tt.target); got != tt.want {
+			t.Errorf("key of %s = %q, want %q", tt.target, got, tt.want)
+		}
+	}
+
+	// Invalidating one namespace by prefix must not drop another.
+	namespaces := map[string]string{
+		"song:1:":        key("/songs/1/text"),
+		"song:10":        key("/songs/10"),
+		"songs:":         key("/songs?page=1"),
+		"search:lyrics:": key("/search?q=a"),
+		"search:songs:":  key("/search/songs?q=a"),
+		"artists:":       key("/artists"),
+		"stats:":         key("/stats/facets?by=year"),
+		"genres:":        key("/genres"),
+		"tags:":          key("/tags"),
+	}
+	for prefix := range namespaces {
+		for other, otherKey := range namespaces {
+			if other != prefix && strings.HasPrefix(otherKey, prefix) {
+				t.Errorf("prefix %q also matches %q", prefix, otherKey)
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/SZabrodskii/music-library/utils/providers"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"time"
+)
+
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type idempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Completed   bool   `json:"completed"`
+	StatusCode  int    `json:"statusCode,omitempty"`
+	Location    string `json:"location,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// IdempotencyMiddleware makes POST, PATCH and DELETE requests carrying an Idempotency-Key header safe to retry:
+// the first request with a key is processed and its response stored for ttl, later requests with the same key
+// get the stored response replayed. Reusing a key for a different request is rejected with 422, and a retry
+// that arrives while the first request is still running with 409. 5xx responses are not stored.
+func IdempotencyMiddleware(cache *providers.CacheProvider, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || c.Request.Method == http.MethodGet {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		cacheKey := providers.PersistentKeyPrefix + "idempotency:" + key
+		fingerprint := requestFingerprint(c, body)
+		pending, _ := json.Marshal(&idempotencyRecord{Fingerprint: fingerprint})
+
+		reserved, err := cache.SetIfAbsent(cacheKey, pending, ttl)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !reserved {
+			replay(c, cache, cacheKey, fingerprint)
+			return
+		}
+
+		w := &writer{body: &bytes.Buffer{}, ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			cache.DeleteFromCache(cacheKey)
+			return
+		}
+		record, _ := json.Marshal(&idempotencyRecord{
+			Fingerprint: fingerprint,
+			Completed:   true,
+			StatusCode:  c.Writer.Status(),
+			Location:    c.Writer.Header().Get("Location"),
+			Body:        w.body.Bytes(),
+		})
+		cache.SetToCache(cacheKey, record, ttl)
+	}
+}
+
+func replay(c *gin.Context, cache *providers.CacheProvider, cacheKey, fingerprint string) {
+	val, ok := cache.GetShared(cacheKey)
+	var record idempotencyRecord
+	if !ok || json.Unmarshal(val, &record) != nil {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "a request with this Idempotency-Key is being processed"})
+		return
+	}
+	if record.Fingerprint != fingerprint {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was already used for a different request"})
+		return
+	}
+	if !record.Completed {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "a request with this Idempotency-Key is being processed"})
+		return
+	}
+
+	if record.Location != "" {
+		c.Header("Location", record.Location)
+	}
+	c.Header("Idempotent-Replayed", "true")
+	c.Data(record.StatusCode, "application/json; charset=utf-8", record.Body)
+	c.Abort()
+}
+
+func requestFingerprint(c *gin.Context, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Request.Method + " " + c.Request.URL.RequestURI() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
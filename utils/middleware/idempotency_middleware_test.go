@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"github.com/SZabrodskii/music-library/utils/providers"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newIdempotencyRouter(t *testing.T, handler gin.HandlerFunc) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	redis, err := providers.NewRedisProvider(&providers.RedisProviderConfig{Addr: miniredis.RunT(t).Addr()})
+	if err != nil {
+		t.Fatalf("NewRedisProvider() error = %v", err)
+	}
+	cache := providers.NewCacheProvider(zap.NewNop(), redis)
+
+	router := gin.New()
+	router.Use(IdempotencyMiddleware(cache, time.Hour))
+	router.Any("/songs", handler)
+	return router
+}
+
+func doRequest(router *gin.Engine, method, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/songs", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyMiddlewareReplays(t *testing.T) {
+	calls := 0
+	router := newIdempotencyRouter(t, func(c *gin.Context) {
+		calls++
+		c.Header("Location", "/api/v1/jobs/1")
+		c.JSON(http.StatusAccepted, gin.H{"call": calls})
+	})
+
+	first := doRequest(router, http.MethodPost, "key", `{"song":"a"}`)
+	second := doRequest(router, http.MethodPost, "key", `{"song":"a"}`)
+
+	if calls != 1 {
+		t.Errorf("handler called %d times, want 1", calls)
+	}
+	if second.Code != first.Code || second.Body.String() != first.Body.String() {
+		t.Errorf("replayed %d %s, want %d %s", second.Code, second.Body, first.Code, first.Body)
+	}
+	if got := second.Header().Get("Location"); got != "/api/v1/jobs/1" {
+		t.Errorf("replayed Location = %q, want /api/v1/jobs/1", got)
+	}
+	if got := second.Header().Get("Idempotent-Replayed"); got != "true" {
+		t.Errorf("Idempotent-Replayed = %q, want true", got)
+	}
+}
+
+func TestIdempotencyMiddlewareRejectsReusedKey(t *testing.T) {
+	router := newIdempotencyRouter(t, func(c *gin.Context) {
+		c.JSON(http.StatusAccepted, gin.H{})
+	})
+
+	doRequest(router, http.MethodPost, "key", `{"song":"a"}`)
+	if w := doRequest(router, http.MethodPost, "key", `{"song":"b"}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("different body with the same key answered %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+	if w := doRequest(router, http.MethodPatch, "key", `{"song":"a"}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("different method with the same key answered %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestIdempotencyMiddlewareConflictsWhileInFlight(t *testing.T) {
+	var router *gin.Engine
+	var retried *httptest.ResponseRecorder
+	router = newIdempotencyRouter(t, func(c *gin.Context) {
+		if retried == nil {
+			retried = doRequest(router, http.MethodPost, "key", `{"song":"a"}`)
+		}
+		c.JSON(http.StatusAccepted, gin.H{})
+	})
+
+	doRequest(router, http.MethodPost, "key", `{"song":"a"}`)
+	if retried.Code != http.StatusConflict {
+		t.Errorf("retry during the first request answered %d, want %d", retried.Code, http.StatusConflict)
+	}
+}
+
+func TestIdempotencyMiddlewareDoesNotStoreServerErrors(t *testing.T) {
+	calls := 0
+	router := newIdempotencyRouter(t, func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "unavailable"})
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{})
+	})
+
+	doRequest(router, http.MethodPost, "key", `{"song":"a"}`)
+	if w := doRequest(router, http.MethodPost, "key", `{"song":"a"}`); w.Code != http.StatusAccepted {
+		t.Errorf("retry after a server error answered %d, want %d", w.Code, http.StatusAccepted)
+	}
+	if calls != 2 {
+		t.Errorf("handler called %d times, want 2", calls)
+	}
+}
+
+func TestIdempotencyMiddlewarePassesThrough(t *testing.T) {
+	calls := 0
+	router := newIdempotencyRouter(t, func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	doRequest(router, http.MethodGet, "key", "")
+	doRequest(router, http.MethodGet, "key", "")
+	doRequest(router, http.MethodPost, "", `{"song":"a"}`)
+	doRequest(router, http.MethodPost, "", `{"song":"a"}`)
+	if calls != 4 {
+		t.Errorf("handler called %d times, want 4", calls)
+	}
+}
@@ -4,8 +4,12 @@ import (
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"math/rand"
+	"strings"
 )
 
+// ActorHeader names who makes a request; it is recorded with the song revisions the request causes.
+const ActorHeader = "X-Actor"
+
 func TraceParentMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if c.Request.Header.Get("traceparent") == "" {
@@ -48,3 +52,12 @@ func GenerateTraceparent() string {
 
 	return fmt.Sprintf("%s-%s-%s-%s", version, traceID, spanID, traceFlags)
 }
+
+// TraceID returns the trace ID part of a traceparent header, or "" if it is malformed.
+func TraceID(traceparent string) string {
+	parts := strings.Split(traceparent, "-")
+	if len(parts) != 4 {
+		return ""
+	}
+	return parts[1]
+}
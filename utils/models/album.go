@@ -0,0 +1,31 @@
+package models
+
+import "gorm.io/gorm"
+
+type Album struct {
+	gorm.Model
+	Title    string `json:"title"`
+	ArtistID *uint  `json:"artistId,omitempty"`
+	// Artist is the name of the album artist; on create and update it is resolved like a song credit.
+	Artist      string        `json:"artist" gorm:"-"`
+	ReleaseDate Date          `json:"releaseDate" swaggertype:"string" format:"date" example:"2006-07-03"`
+	CoverURL    string        `json:"coverUrl"`
+	Tracks      []*AlbumTrack `json:"tracks,omitempty" gorm:"-"`
+}
+
+// AlbumTrack places a song on an album. Disc defaults to 1.
+type AlbumTrack struct {
+	AlbumID     uint  `json:"albumId" gorm:"primaryKey"`
+	SongID      uint  `json:"songId" gorm:"primaryKey"`
+	Disc        int   `json:"disc"`
+	TrackNumber int   `json:"trackNumber"`
+	Song        *Song `json:"song,omitempty" gorm:"-"`
+}
+
+// SongAlbum is an album a song appears on, as returned with the song.
+type SongAlbum struct {
+	AlbumID     uint   `json:"albumId"`
+	Title       string `json:"title"`
+	Disc        int    `json:"disc"`
+	TrackNumber int    `json:"trackNumber"`
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+type ArtistRole string
+
+const (
+	ArtistRolePrimary  ArtistRole = "primary"
+	ArtistRoleFeatured ArtistRole = "featured"
+	ArtistRoleComposer ArtistRole = "composer"
+	ArtistRoleLyricist ArtistRole = "lyricist"
+)
+
+func (r ArtistRole) Valid() bool {
+	switch r {
+	case ArtistRolePrimary, ArtistRoleFeatured, ArtistRoleComposer, ArtistRoleLyricist:
+		return true
+	}
+	return false
+}
+
+type Artist struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Name      string    `json:"name"`
+	// NormalizedName is unique, so that "Muse" and "MUSE" are one artist.
+	NormalizedName string `json:"-"`
+	SongCount      int64  `json:"songCount" gorm:"->;-:migration"`
+}
+
+// SongArtist credits an artist with a role on a song. Position orders the artists of a role.
+type SongArtist struct {
+	SongID   uint       `json:"songId" gorm:"primaryKey"`
+	ArtistID uint       `json:"artistId" gorm:"primaryKey"`
+	Role     ArtistRole `json:"role" gorm:"primaryKey"`
+	Position int        `json:"position"`
+}
+
+// Credit is an artist of a song as exposed with the song.
+type Credit struct {
+	ArtistID uint       `json:"artistId,omitempty"`
+	Name     string     `json:"name"`
+	Role     ArtistRole `json:"role"`
+}
+
+type ArtistCredit struct {
+	Song *Song      `json:"song"`
+	Role ArtistRole `json:"role"`
+}
+
+type ArtistWithSongs struct {
+	Artist *Artist         `json:"artist"`
+	Songs  []*ArtistCredit `json:"songs"`
+}
+
+// NormalizedArtistName identifies an artist regardless of case, punctuation and spacing.
+func NormalizedArtistName(name string) string {
+	return normalize(name)
+}
+
+var (
+	featuringPattern = regexp.MustCompile(`(?i)\s+(?:feat\.?|ft\.?|featuring)\s+`)
+	separatorPattern = regexp.MustCompile(`\s*(?:,|&)\s*`)
+)
+
+// GroupCredits splits a group name such as "Muse feat. A & B" into its primary artist and the featured
+// ones. The primary part is never split further, since names like "Simon & Garfunkel" are one artist.
+func GroupCredits(group string) []*Credit {
+	parts := featuringPattern.Split(group, 2)
+	var credits []*Credit
+	if name := strings.TrimSpace(parts[0]); name != "" {
+		credits = append(credits, &Credit{Name: name, Role: ArtistRolePrimary})
+	}
+	if len(parts) == 2 {
+		for _, name := range separatorPattern.Split(parts[1], -1) {
+			if name = strings.TrimSpace(name); name != "" {
+				credits = append(credits, &Credit{Name: name, Role: ArtistRoleFeatured})
+			}
+		}
+	}
+	return credits
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupCredits(t *testing.T) {
+	tests := []struct {
+		group string
+		want  []*Credit
+	}{
+		{group: "Muse", want: []*Credit{{Name: "Muse", Role: ArtistRolePrimary}}},
+		{group: "Simon & Garfunkel", want: []*Credit{{Name: "Simon & Garfunkel", Role: ArtistRolePrimary}}},
+		{
+			group: "Muse feat. A & B, C",
+			want: []*Credit{
+				{Name: "Muse", Role: ArtistRolePrimary},
+				{Name: "A", Role: ArtistRoleFeatured},
+				{Name: "B", Role: ArtistRoleFeatured},
+				{Name: "C", Role: ArtistRoleFeatured},
+			},
+		},
+		{
+			group: "Muse FT Someone",
+			want:  []*Credit{{Name: "Muse", Role: ArtistRolePrimary}, {Name: "Someone", Role: ArtistRoleFeatured}},
+		},
+		{
+			group: "Muse featuring Someone",
+			want:  []*Credit{{Name: "Muse", Role: ArtistRolePrimary}, {Name: "Someone", Role: ArtistRoleFeatured}},
+		},
+		{group: "Featherweight", want: []*Credit{{Name: "Featherweight", Role: ArtistRolePrimary}}},
+		{group: "  ", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.group, func(t *testing.T) {
+			if got := GroupCredits(tt.group); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GroupCredits(%q) = %+v, want %+v", tt.group, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DateLayout is the ISO 8601 form dates are stored and served in.
+const DateLayout = "2006-01-02"
+
+// dateLayouts are the formats accepted from clients and upstream sources, such as the song info API's 16.07.2006.
+var dateLayouts = []string{DateLayout, "02.01.2006", "2006/01/02", time.RFC3339, "2006-01", "2006"}
+
+// Date is a calendar date without time of day. The zero Date means unknown and is stored as NULL
+// and served as null.
+type Date struct {
+	time.Time
+}
+
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{Time: time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// ParseDate parses value in any of the known formats. A blank value gives the zero Date.
+func ParseDate(value string) (Date, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Date{}, nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return NewDate(t.Date()), nil
+		}
+	}
+	return Date{}, fmt.Errorf("%q is not a date in %s or DD.MM.YYYY format", value, DateLayout)
+}
+
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(DateLayout)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = Date{}
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := ParseDate(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+func (d Date) Value() (driver.Value, error) {
+	if d.IsZero() {
+		return nil, nil
+	}
+	return d.String(), nil
+}
+
+func (d *Date) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*d = Date{}
+	case time.Time:
+		*d = NewDate(v.Date())
+	case string:
+		return d.parse(v)
+	case []byte:
+		return d.parse(string(v))
+	default:
+		return fmt.Errorf("cannot scan %T into Date", value)
+	}
+	return nil
+}
+
+func (d *Date) parse(value string) error {
+	parsed, err := ParseDate(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+func (Date) GormDataType() string {
+	return "date"
+}
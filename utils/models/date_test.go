@@ -0,0 +1,43 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Date
+		wantErr bool
+	}{
+		{value: "", want: Date{}},
+		{value: "   ", want: Date{}},
+		{value: "2006-07-16", want: NewDate(2006, time.July, 16)},
+		{value: " 16.07.2006 ", want: NewDate(2006, time.July, 16)},
+		{value: "2006/07/16", want: NewDate(2006, time.July, 16)},
+		{value: "2006-07-16T23:30:00+02:00", want: NewDate(2006, time.July, 16)},
+		{value: "2006-07", want: NewDate(2006, time.July, 1)},
+		{value: "2006", want: NewDate(2006, time.January, 1)},
+		{value: "07/16/2006", wantErr: true},
+		{value: "2006-02-30", wantErr: true},
+		{value: "someday", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseDate(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseDate(%q) = %v, want an error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDate(%q) error = %v", tt.value, err)
+			}
+			if !got.Equal(tt.want.Time) {
+				t.Errorf("ParseDate(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
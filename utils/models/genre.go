@@ -0,0 +1,64 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Genre is a node of the genre taxonomy. A song in a genre also counts as being in all its ancestors.
+type Genre struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Name      string    `json:"name"`
+	// Slug is unique and identifies the genre in filters and URLs.
+	Slug     string `json:"slug"`
+	ParentID *uint  `json:"parentId,omitempty"`
+	// SongCount includes the songs of the descendants.
+	SongCount int64    `json:"songCount" gorm:"->;-:migration"`
+	Children  []*Genre `json:"children,omitempty" gorm:"-"`
+}
+
+type SongGenre struct {
+	SongID  uint `json:"songId" gorm:"primaryKey"`
+	GenreID uint `json:"genreId" gorm:"primaryKey"`
+}
+
+// Tag is a free-form label such as "workout" or "covers".
+type Tag struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	SongCount int64     `json:"songCount" gorm:"->;-:migration"`
+}
+
+type SongTag struct {
+	SongID uint `json:"songId" gorm:"primaryKey"`
+	TagID  uint `json:"tagId" gorm:"primaryKey"`
+}
+
+// Label is a genre or tag as exposed with a song.
+type Label struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// Facet is the number of songs carrying a label.
+type Facet struct {
+	Slug  string `json:"slug"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// SongFacets counts the songs matching a list request by genre and by tag.
+type SongFacets struct {
+	Genres []*Facet `json:"genres"`
+	Tags   []*Facet `json:"tags"`
+}
+
+// Slug turns a genre or tag name into its identifier: "Alternative Rock" becomes "alternative-rock".
+func Slug(name string) string {
+	return strings.ReplaceAll(normalize(name), " ", "-")
+}
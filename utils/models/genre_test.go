@@ -0,0 +1,25 @@
+package models
+
+import "testing"
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "Rock", want: "rock"},
+		{name: "Alternative Rock", want: "alternative-rock"},
+		{name: "  road   trip! ", want: "road-trip"},
+		{name: "Drum & Bass", want: "drum-bass"},
+		{name: "alternative-rock", want: "alternative-rock"},
+		{name: "Música Popular", want: "música-popular"},
+		{name: "!!", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slug(tt.name); got != tt.want {
+				t.Errorf("Slug(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
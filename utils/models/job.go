@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "pending"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+const (
+	JobTypeAddSong     = "add_song"
+	JobTypeUpdateSong  = "update_song"
+	JobTypeDeleteSong  = "delete_song"
+	JobTypeRefreshSong = "refresh_song"
+	JobTypeRevertSong  = "revert_song"
+)
+
+type Job struct {
+	ID        string    `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Type      string    `json:"type"`
+	Status    JobStatus `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	SongID    *uint     `json:"songId,omitempty"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxMessage is a queue message stored in the same transaction as the change that produced it
+// and published by the outbox relay.
+type OutboxMessage struct {
+	ID        uint64 `gorm:"primaryKey"`
+	CreatedAt time.Time
+	MessageID string
+	Queue     string
+	Payload   json.RawMessage
+	Attempts  int
+	LastError string
+	SentAt    *time.Time
+}
+
+// ProcessedMessage records a message ID handled by a consumer, written in the same transaction as
+// the message's changes so that redeliveries are skipped.
+type ProcessedMessage struct {
+	MessageID   string `gorm:"primaryKey"`
+	Queue       string
+	ProcessedAt time.Time
+}
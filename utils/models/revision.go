@@ -0,0 +1,82 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	RevisionActionCreate  = "create"
+	RevisionActionUpdate  = "update"
+	RevisionActionDelete  = "delete"
+	RevisionActionRefresh = "refresh"
+	RevisionActionRevert  = "revert"
+)
+
+// SongRevision records a change to a song or its verses: the state after the change and how it differs
+// from the previous revision. Revisions of a song are numbered from 1.
+type SongRevision struct {
+	ID        uint            `json:"-" gorm:"primaryKey"`
+	CreatedAt time.Time       `json:"createdAt"`
+	SongID    uint            `json:"songId"`
+	Revision  int             `json:"revision"`
+	Action    string          `json:"action"`
+	Snapshot  json.RawMessage `json:"snapshot" swaggertype:"object"`
+	Diff      json.RawMessage `json:"diff" swaggertype:"object"`
+	// Actor is who asked for the change, from the X-Actor header; "system" for scheduled refreshes.
+	Actor   string `json:"actor"`
+	TraceID string `json:"traceId,omitempty"`
+}
+
+// SongSnapshot is the state of a song as stored in a revision.
+type SongSnapshot struct {
+	GroupName   string   `json:"group"`
+	SongName    string   `json:"song"`
+	ReleaseDate Date     `json:"releaseDate"`
+	Link        string   `json:"link"`
+	Verses      []string `json:"verses"`
+	Deleted     bool     `json:"deleted,omitempty"`
+}
+
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// DiffSnapshots returns the fields that differ between before, which is nil for a new song, and after.
+// Verses are compared by position and keyed verses.1, verses.2 and so on.
+func DiffSnapshots(before, after *SongSnapshot) map[string]*FieldChange {
+	if before == nil {
+		before = &SongSnapshot{}
+	}
+	diff := make(map[string]*FieldChange)
+	if before.GroupName != after.GroupName {
+		diff["group"] = &FieldChange{From: before.GroupName, To: after.GroupName}
+	}
+	if before.SongName != after.SongName {
+		diff["song"] = &FieldChange{From: before.SongName, To: after.SongName}
+	}
+	if !before.ReleaseDate.Equal(after.ReleaseDate.Time) {
+		diff["releaseDate"] = &FieldChange{From: before.ReleaseDate, To: after.ReleaseDate}
+	}
+	if before.Link != after.Link {
+		diff["link"] = &FieldChange{From: before.Link, To: after.Link}
+	}
+	if before.Deleted != after.Deleted {
+		diff["deleted"] = &FieldChange{From: before.Deleted, To: after.Deleted}
+	}
+	for i := 0; i < max(len(before.Verses), len(after.Verses)); i++ {
+		var from, to interface{}
+		if i < len(before.Verses) {
+			from = before.Verses[i]
+		}
+		if i < len(after.Verses) {
+			to = after.Verses[i]
+		}
+		if from != to {
+			diff[fmt.Sprintf("verses.%d", i+1)] = &FieldChange{From: from, To: to}
+		}
+	}
+	return diff
+}
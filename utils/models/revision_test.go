@@ -0,0 +1,85 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	song := &SongSnapshot{
+		GroupName:   "Muse",
+		SongName:    "Uprising",
+		ReleaseDate: NewDate(2009, time.September, 7),
+		Link:        "https://example.com/uprising",
+		Verses:      []string{"first", "second"},
+	}
+	with := func(change func(s *SongSnapshot)) *SongSnapshot {
+		changed := *song
+		changed.Verses = append([]string(nil), song.Verses...)
+		change(&changed)
+		return &changed
+	}
+
+	tests := []struct {
+		name   string
+		before *SongSnapshot
+		after  *SongSnapshot
+		want   map[string]*FieldChange
+	}{
+		{name: "unchanged", before: song, after: with(func(s *SongSnapshot) {}), want: map[string]*FieldChange{}},
+		{
+			name:   "new song",
+			before: nil,
+			after:  song,
+			want: map[string]*FieldChange{
+				"group":       {From: "", To: "Muse"},
+				"song":        {From: "", To: "Uprising"},
+				"releaseDate": {From: Date{}, To: song.ReleaseDate},
+				"link":        {From: "", To: "https://example.com/uprising"},
+				"verses.1":    {From: nil, To: "first"},
+				"verses.2":    {From: nil, To: "second"},
+			},
+		},
+		{
+			name:   "fields",
+			before: song,
+			after: with(func(s *SongSnapshot) {
+				s.SongName = "Resistance"
+				s.ReleaseDate = Date{}
+			}),
+			want: map[string]*FieldChange{
+				"song":        {From: "Uprising", To: "Resistance"},
+				"releaseDate": {From: song.ReleaseDate, To: Date{}},
+			},
+		},
+		{
+			name:   "changed and removed verses",
+			before: song,
+			after:  with(func(s *SongSnapshot) { s.Verses = []string{"FIRST"} }),
+			want: map[string]*FieldChange{
+				"verses.1": {From: "first", To: "FIRST"},
+				"verses.2": {From: "second", To: nil},
+			},
+		},
+		{
+			name:   "added verse",
+			before: song,
+			after:  with(func(s *SongSnapshot) { s.Verses = append(s.Verses, "third") }),
+			want:   map[string]*FieldChange{"verses.3": {From: nil, To: "third"}},
+		},
+		{
+			name:   "deleted",
+			before: song,
+			after:  with(func(s *SongSnapshot) { s.Deleted = true }),
+			want:   map[string]*FieldChange{"deleted": {From: false, To: true}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffSnapshots(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffSnapshots() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package models
+
+type LyricsMatch struct {
+	SongID     uint    `json:"songId"`
+	GroupName  string  `json:"group"`
+	SongName   string  `json:"song"`
+	VerseIndex int     `json:"verseIndex"`
+	Snippet    string  `json:"snippet"`
+	Rank       float64 `json:"rank"`
+}
+
+type SongMatch struct {
+	Song
+	Score float64 `json:"score"`
+}
@@ -1,13 +1,40 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"gorm.io/gorm"
+	"strings"
+	"time"
+	"unicode"
+)
 
 type Song struct {
 	gorm.Model
 	GroupName   string `json:"group"`
 	SongName    string `json:"song"`
-	ReleaseDate string `json:"releaseDate"`
+	ReleaseDate Date   `json:"releaseDate" swaggertype:"string" format:"date" example:"2006-07-16"`
 	Link        string `json:"link"`
+	// NormalizedKey is unique among songs that are not deleted.
+	NormalizedKey string `json:"-"`
+	// EnrichedAt is when the details were last fetched from the enrichers.
+	EnrichedAt *time.Time `json:"enrichedAt,omitempty"`
+	// Artists are the credited artists. On add and update they list credits beyond those parsed from the group name.
+	Artists []*Credit `json:"artists,omitempty" gorm:"-"`
+	// Genres and Tags are set with the attach endpoints and ignored on add and update.
+	Genres []*Label `json:"genres,omitempty" gorm:"-"`
+	Tags   []*Label `json:"tags,omitempty" gorm:"-"`
+}
+
+// NormalizedKey identifies a song regardless of case, punctuation and spacing, so that
+// "Muse – Supermassive Black Hole" and "muse - supermassive  black hole!" share a key.
+func NormalizedKey(group, song string) string {
+	return normalize(group) + "|" + normalize(song)
+}
+
+func normalize(value string) string {
+	words := strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
 }
 
 type SongDetail struct {
@@ -15,3 +42,10 @@ type SongDetail struct {
 	Text        string `json:"text"`
 	Link        string `json:"link"`
 }
+
+type SongWithDetail struct {
+	Song       *Song        `json:"song"`
+	VerseCount int64        `json:"verseCount"`
+	Detail     SongDetail   `json:"detail"`
+	Albums     []*SongAlbum `json:"albums"`
+}
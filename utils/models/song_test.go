@@ -0,0 +1,26 @@
+package models
+
+import "testing"
+
+func TestNormalizedKey(t *testing.T) {
+	tests := []struct {
+		group string
+		song  string
+		want  string
+	}{
+		{group: "Muse", song: "Supermassive Black Hole", want: "muse|supermassive black hole"},
+		{group: "muse ", song: "supermassive  black hole!", want: "muse|supermassive black hole"},
+		{group: "Muse –", song: "Supermassive-Black_Hole", want: "muse|supermassive black hole"},
+		{group: "AC/DC", song: "T.N.T.", want: "ac dc|t n t"},
+		{group: "Sigur Rós", song: "Hoppípolla", want: "sigur rós|hoppípolla"},
+		{group: "Blink-182", song: "All the Small Things", want: "blink 182|all the small things"},
+		{group: "", song: "!!!", want: "|"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.group+" - "+tt.song, func(t *testing.T) {
+			if got := NormalizedKey(tt.group, tt.song); got != tt.want {
+				t.Errorf("NormalizedKey(%q, %q) = %q, want %q", tt.group, tt.song, got, tt.want)
+			}
+		})
+	}
+}
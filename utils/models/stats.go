@@ -0,0 +1,18 @@
+package models
+
+// FacetStats aggregates the songs matching a filter into buckets.
+type FacetStats struct {
+	By            string    `json:"by"`
+	Total         int64     `json:"total"`
+	AverageVerses float64   `json:"averageVerses"`
+	Buckets       []*Bucket `json:"buckets"`
+}
+
+type Bucket struct {
+	// Key is the group name, the release year or the genre slug; it is null for songs without a release date.
+	Key *string `json:"key"`
+	// Name is the genre name when bucketing by genre.
+	Name          string  `json:"name,omitempty"`
+	Count         int64   `json:"count"`
+	AverageVerses float64 `json:"averageVerses"`
+}
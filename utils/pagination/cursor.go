@@ -0,0 +1,52 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks the position after the last row of a keyset page: the sort it was issued for
+// and the values of the sort keys of that row.
+type Cursor struct {
+	Sort   string   `json:"sort"`
+	Values []string `json:"values"`
+}
+
+// EncodeCursor serializes the cursor as base64url(payload).base64url(hmac-sha256(payload)).
+func EncodeCursor(cursor *Cursor, secret []byte) (string, error) {
+	payload, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + sign(encoded, secret), nil
+}
+
+// DecodeCursor verifies the signature and returns the cursor, or ErrInvalidCursor if it was tampered with.
+func DecodeCursor(raw string, secret []byte) (*Cursor, error) {
+	encoded, signature, ok := strings.Cut(raw, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(sign(encoded, secret))) {
+		return nil, ErrInvalidCursor
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor Cursor
+	if err := json.Unmarshal(payload, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+func sign(encoded string, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,65 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	secret := []byte("secret")
+	tests := []*Cursor{
+		{Sort: "id", Values: []string{"1"}},
+		{Sort: "group,-releaseDate,id", Values: []string{"Muse, feat. \"A\"", "0001-01-01", "42"}},
+		{Sort: "id", Values: []string{}},
+	}
+	for _, cursor := range tests {
+		t.Run(cursor.Sort, func(t *testing.T) {
+			raw, err := EncodeCursor(cursor, secret)
+			if err != nil {
+				t.Fatalf("EncodeCursor() error = %v", err)
+			}
+			got, err := DecodeCursor(raw, secret)
+			if err != nil {
+				t.Fatalf("DecodeCursor(%q) error = %v", raw, err)
+			}
+			if !reflect.DeepEqual(got, cursor) {
+				t.Errorf("DecodeCursor(%q) = %+v, want %+v", raw, got, cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejectsTampering(t *testing.T) {
+	secret := []byte("secret")
+	raw, err := EncodeCursor(&Cursor{Sort: "id", Values: []string{"1"}}, secret)
+	if err != nil {
+		t.Fatalf("EncodeCursor() error = %v", err)
+	}
+	encoded, signature, _ := strings.Cut(raw, ".")
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"sort":"id","values":["1000"]}`))
+
+	tests := []struct {
+		name   string
+		raw    string
+		secret []byte
+	}{
+		{name: "empty", raw: "", secret: secret},
+		{name: "no signature", raw: encoded, secret: secret},
+		{name: "empty signature", raw: encoded + ".", secret: secret},
+		{name: "changed payload", raw: forged + "." + signature, secret: secret},
+		{name: "changed signature", raw: encoded + "." + strings.ToUpper(signature), secret: secret},
+		{name: "other secret", raw: raw, secret: []byte("other")},
+		{name: "signed garbage", raw: "!!." + sign("!!", secret), secret: secret},
+		{name: "signed non-JSON", raw: "bm90IGpzb24." + sign("bm90IGpzb24", secret), secret: secret},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tt.raw, tt.secret); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCursor(%q) error = %v, want ErrInvalidCursor", tt.raw, err)
+			}
+		})
+	}
+}
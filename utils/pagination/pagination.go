@@ -0,0 +1,91 @@
+package pagination
+
+import (
+	"net/url"
+	"strconv"
+)
+
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+type Pagination struct {
+	Total      int64  `json:"total"`
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"pageSize,omitempty"`
+	TotalPages int    `json:"totalPages,omitempty"`
+	Limit      int    `json:"limit,omitempty"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	Next       string `json:"next,omitempty"`
+	Prev       string `json:"prev,omitempty"`
+}
+
+// Normalize converts the raw page and pageSize query values, falling back to defaults for invalid input.
+func Normalize(page, pageSize string) (int, int) {
+	pageInt, err := strconv.Atoi(page)
+	if err != nil || pageInt < 1 {
+		pageInt = 1
+	}
+	pageSizeInt, err := strconv.Atoi(pageSize)
+	if err != nil || pageSizeInt < 1 {
+		pageSizeInt = DefaultPageSize
+	}
+	if pageSizeInt > MaxPageSize {
+		pageSizeInt = MaxPageSize
+	}
+	return pageInt, pageSizeInt
+}
+
+// NormalizeLimit converts the raw limit query value used in cursor mode.
+func NormalizeLimit(limit string) int {
+	_, limitInt := Normalize("1", limit)
+	return limitInt
+}
+
+func Offset(page, pageSize int) int {
+	return (page - 1) * pageSize
+}
+
+// New builds the pagination metadata. Next and prev links are relative references ("?page=2&...")
+// built from query, so they resolve against whichever path served the response.
+func New(total int64, page, pageSize int, query url.Values) Pagination {
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+	p := Pagination{
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}
+	if page < totalPages {
+		p.Next = link(query, "page", strconv.Itoa(page+1), "pageSize", strconv.Itoa(pageSize))
+	}
+	if page > 1 {
+		p.Prev = link(query, "page", strconv.Itoa(min(page-1, max(totalPages, 1))), "pageSize", strconv.Itoa(pageSize))
+	}
+	return p
+}
+
+// NewCursor builds the metadata of a keyset page. nextCursor is empty on the last page.
+func NewCursor(total int64, limit int, nextCursor string, query url.Values) Pagination {
+	p := Pagination{
+		Total:      total,
+		Limit:      limit,
+		NextCursor: nextCursor,
+	}
+	if nextCursor != "" {
+		p.Next = link(query, "cursor", nextCursor, "limit", strconv.Itoa(limit))
+	}
+	return p
+}
+
+func link(query url.Values, keyValues ...string) string {
+	values := url.Values{}
+	for key, value := range query {
+		values[key] = value
+	}
+	for i := 0; i+1 < len(keyValues); i += 2 {
+		values.Set(keyValues[i], keyValues[i+1])
+	}
+	return "?" + values.Encode()
+}
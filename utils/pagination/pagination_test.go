@@ -0,0 +1,99 @@
+package pagination
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		page, pageSize         string
+		wantPage, wantPageSize int
+	}{
+		{page: "", pageSize: "", wantPage: 1, wantPageSize: DefaultPageSize},
+		{page: "3", pageSize: "20", wantPage: 3, wantPageSize: 20},
+		{page: "0", pageSize: "-5", wantPage: 1, wantPageSize: DefaultPageSize},
+		{page: "two", pageSize: "ten", wantPage: 1, wantPageSize: DefaultPageSize},
+		{page: "1", pageSize: "1000", wantPage: 1, wantPageSize: MaxPageSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.page+"/"+tt.pageSize, func(t *testing.T) {
+			page, pageSize := Normalize(tt.page, tt.pageSize)
+			if page != tt.wantPage || pageSize != tt.wantPageSize {
+				t.Errorf("Normalize(%q, %q) = %d, %d, want %d, %d", tt.page, tt.pageSize, page, pageSize, tt.wantPage, tt.wantPageSize)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	query := url.Values{"filters": {"group:eq:Muse"}, "page": {"2"}}
+	tests := []struct {
+		name           string
+		total          int64
+		page, pageSize int
+		want           Pagination
+	}{
+		{
+			name:  "empty",
+			total: 0, page: 1, pageSize: 10,
+			want: Pagination{Total: 0, Page: 1, PageSize: 10, TotalPages: 0},
+		},
+		{
+			name:  "single page",
+			total: 10, page: 1, pageSize: 10,
+			want: Pagination{Total: 10, Page: 1, PageSize: 10, TotalPages: 1},
+		},
+		{
+			name:  "middle page",
+			total: 25, page: 2, pageSize: 10,
+			want: Pagination{
+				Total: 25, Page: 2, PageSize: 10, TotalPages: 3,
+				Next: "?filters=group%3Aeq%3AMuse&page=3&pageSize=10",
+				Prev: "?filters=group%3Aeq%3AMuse&page=1&pageSize=10",
+			},
+		},
+		{
+			name:  "past the last page",
+			total: 25, page: 7, pageSize: 10,
+			want: Pagination{
+				Total: 25, Page: 7, PageSize: 10, TotalPages: 3,
+				Prev: "?filters=group%3Aeq%3AMuse&page=3&pageSize=10",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.total, tt.page, tt.pageSize, query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("New() = %+v, want %+v", got, tt.want)
+			}
+			if query.Get("page") != "2" {
+				t.Errorf("New() changed the query to %v", query)
+			}
+		})
+	}
+}
+
+func TestNewCursor(t *testing.T) {
+	query := url.Values{"sort": {"group"}, "cursor": {"old"}}
+	tests := []struct {
+		name       string
+		nextCursor string
+		want       Pagination
+	}{
+		{name: "last page", want: Pagination{Total: 25, Limit: 10}},
+		{
+			name:       "more pages",
+			nextCursor: "abc.def",
+			want:       Pagination{Total: 25, Limit: 10, NextCursor: "abc.def", Next: "?cursor=abc.def&limit=10&sort=group"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewCursor(25, 10, tt.nextCursor, query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewCursor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
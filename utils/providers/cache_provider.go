@@ -5,10 +5,18 @@ import (
 	"errors"
 	"github.com/go-redis/redis/v8"
 	"go.uber.org/zap"
+	"strings"
 	"sync"
 	"time"
 )
 
+// PersistentKeyPrefix marks keys that hold state rather than cached responses; ClearCache keeps them.
+const PersistentKeyPrefix = "persistent:"
+
+// invalidationChannel carries the keys deleted by any instance, so that every instance drops them from its
+// local cache. A payload ending in * is a prefix.
+const invalidationChannel = "cache:invalidate"
+
 type CacheProvider struct {
 	logger     *zap.Logger
 	redis      *redis.Client
@@ -26,6 +34,7 @@ func NewCacheProvider(logger *zap.Logger, redisProvider *RedisProvider) *CachePr
 		mu:         sync.RWMutex{},
 	}
 	go cp.revalidateCache()
+	cp.subscribeInvalidations()
 	return cp
 }
 
@@ -37,18 +46,60 @@ type CacheItem struct {
 func (c *CacheProvider) revalidateCache() {
 	for {
 		time.Sleep(time.Minute)
+		c.mu.Lock()
 		for key, val := range c.localCache {
-			if val.TTL.After(time.Now()) {
+			if val.TTL.Before(time.Now()) {
 				delete(c.localCache, key)
 			}
 
 		}
+		c.mu.Unlock()
 	}
 }
 
+func (c *CacheProvider) subscribeInvalidations() {
+	ctx := context.Background()
+	pubsub := c.redis.Subscribe(ctx, invalidationChannel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		c.logger.Error("Failed to subscribe to cache invalidations", zap.Error(err))
+	}
+
+	go func() {
+		for msg := range pubsub.Channel() {
+			if prefix := strings.TrimSuffix(msg.Payload, "*"); prefix != msg.Payload {
+				c.dropLocalPrefix(prefix)
+			} else {
+				c.dropLocal(msg.Payload)
+			}
+		}
+	}()
+}
+
+func (c *CacheProvider) publishInvalidation(ctx context.Context, payload string) {
+	if err := c.redis.Publish(ctx, invalidationChannel, payload).Err(); err != nil {
+		c.logger.Error("Failed to publish cache invalidation", zap.String("key", payload), zap.Error(err))
+	}
+}
+
+func (c *CacheProvider) dropLocal(key string) {
+	c.mu.Lock()
+	delete(c.localCache, key)
+	c.mu.Unlock()
+}
+
+func (c *CacheProvider) dropLocalPrefix(prefix string) {
+	c.mu.Lock()
+	for key := range c.localCache {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.localCache, key)
+		}
+	}
+	c.mu.Unlock()
+}
+
 func (c *CacheProvider) GetFromCache(key string) ([]byte, bool) {
 	c.mu.RLock()
-	if val, ok := c.localCache[key]; ok {
+	if val, ok := c.localCache[key]; ok && val.TTL.After(time.Now()) {
 		c.mu.RUnlock()
 		return val.Body, true
 	}
@@ -90,13 +141,10 @@ func (c *CacheProvider) SetToCache(key string, value []byte, ttl time.Duration)
 }
 
 func (c *CacheProvider) DeleteFromCache(key string) {
-	c.mu.Lock()
-	if _, ok := c.localCache[key]; ok {
-		delete(c.localCache, key)
-	}
-	c.mu.Unlock()
+	c.dropLocal(key)
 
 	ctx := context.Background()
+	defer c.publishInvalidation(ctx, key)
 	err := c.redis.Del(ctx, key).Err()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
@@ -109,14 +157,74 @@ func (c *CacheProvider) DeleteFromCache(key string) {
 	}
 }
 
+// DeleteByPrefix drops every cached entry whose key starts with prefix, on every instance.
+func (c *CacheProvider) DeleteByPrefix(prefix string) {
+	c.dropLocalPrefix(prefix)
+
+	ctx := context.Background()
+	defer c.publishInvalidation(ctx, prefix+"*")
+
+	var keys []string
+	iter := c.redis.Scan(ctx, 0, prefix+"*", 1000).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		c.logger.Error("Failed to scan Redis", zap.String("prefix", prefix), zap.Error(err))
+		return
+	}
+	if len(keys) == 0 {
+		return
+	}
+
+	if err := c.redis.Del(ctx, keys...).Err(); err != nil {
+		c.logger.Error("Failed to delete from Redis", zap.String("prefix", prefix), zap.Error(err))
+	}
+}
+
+// SetIfAbsent stores value in Redis only if key does not exist yet and reports whether it was stored.
+// It bypasses the local cache so that all instances agree on the outcome.
+func (c *CacheProvider) SetIfAbsent(key string, value []byte, ttl time.Duration) (bool, error) {
+	return c.redis.SetNX(context.Background(), key, value, ttl).Result()
+}
+
+// GetShared reads key from Redis only, for values that other instances may change.
+func (c *CacheProvider) GetShared(key string) ([]byte, bool) {
+	val, err := c.redis.Get(context.Background(), key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			c.logger.Error("Failed to get from Redis", zap.Error(err))
+		}
+		return nil, false
+	}
+	return val, true
+}
+
+// ClearCache drops every cached response, keeping keys under PersistentKeyPrefix.
 func (c *CacheProvider) ClearCache() {
 	c.mu.Lock()
 	c.localCache = make(map[string]*CacheItem)
 	c.mu.Unlock()
 
 	ctx := context.Background()
+	defer c.publishInvalidation(ctx, "*")
+
+	var keys []string
+	iter := c.redis.Scan(ctx, 0, "*", 1000).Iterator()
+	for iter.Next(ctx) {
+		if !strings.HasPrefix(iter.Val(), PersistentKeyPrefix) {
+			keys = append(keys, iter.Val())
+		}
+	}
+	if err := iter.Err(); err != nil {
+		c.logger.Error("Failed to clear Redis", zap.Error(err))
+		return
+	}
+	if len(keys) == 0 {
+		return
+	}
 
-	if err := c.redis.FlushDB(ctx).Err(); err != nil {
+	if err := c.redis.Del(ctx, keys...).Err(); err != nil {
 		c.logger.Error("Failed to clear Redis", zap.Error(err))
 	} else {
 		c.logger.Debug("Redis cache cleared successfully")
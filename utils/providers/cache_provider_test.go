@@ -0,0 +1,64 @@
+package providers
+
+import (
+	"github.com/alicebob/miniredis/v2"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestCacheProviderInvalidatesOtherInstances(t *testing.T) {
+	redis, err := NewRedisProvider(&RedisProviderConfig{Addr: miniredis.RunT(t).Addr()})
+	if err != nil {
+		t.Fatalf("NewRedisProvider() error = %v", err)
+	}
+	gateway := NewCacheProvider(zap.NewNop(), redis)
+	service := NewCacheProvider(zap.NewNop(), redis)
+
+	tests := []struct {
+		name       string
+		key        string
+		invalidate func()
+	}{
+		{name: "key", key: "song:1", invalidate: func() { service.DeleteFromCache("song:1") }},
+		{name: "prefix", key: "songs:1:10", invalidate: func() { service.DeleteByPrefix("songs:") }},
+		{name: "clear", key: "albums::1:10", invalidate: service.ClearCache},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway.SetToCache(tt.key, []byte("stale"), time.Hour)
+			tt.invalidate()
+
+			deadline := time.Now().Add(time.Second)
+			for {
+				if _, ok := gateway.GetFromCache(tt.key); !ok {
+					return
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("%s is still in the other instance's local cache", tt.key)
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestCacheProviderKeepsOtherKeys(t *testing.T) {
+	redis, err := NewRedisProvider(&RedisProviderConfig{Addr: miniredis.RunT(t).Addr()})
+	if err != nil {
+		t.Fatalf("NewRedisProvider() error = %v", err)
+	}
+	cache := NewCacheProvider(zap.NewNop(), redis)
+
+	cache.SetToCache("song:1", []byte("song"), time.Hour)
+	cache.SetToCache("song:10", []byte("other song"), time.Hour)
+	cache.DeleteFromCache("song:1")
+	time.Sleep(50 * time.Millisecond)
+
+	if _, ok := cache.GetFromCache("song:1"); ok {
+		t.Error("song:1 is still cached")
+	}
+	if body, ok := cache.GetFromCache("song:10"); !ok || string(body) != "other song" {
+		t.Errorf("song:10 = %q, %v, want it kept", body, ok)
+	}
+}
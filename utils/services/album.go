@@ -0,0 +1,122 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"github.com/SZabrodskii/music-library/utils/pagination"
+	"net/http"
+	"net/url"
+)
+
+type GetAlbumsRequest struct {
+	Page     string `json:"page"`
+	PageSize string `json:"pageSize"`
+}
+
+type GetAlbumsResponse struct {
+	Albums []*models.Album `json:"albums"`
+	pagination.Pagination
+}
+
+type GetAlbumRequest struct {
+	AlbumId string `json:"albumId"`
+}
+
+type CreateAlbumRequest struct {
+	Album models.Album `json:"album"`
+}
+
+type UpdateAlbumRequest struct {
+	AlbumId string       `json:"albumId"`
+	Album   models.Album `json:"album"`
+}
+
+type DeleteAlbumRequest struct {
+	AlbumId string `json:"albumId"`
+}
+
+func (c *SongServiceClient) GetAlbums(req *GetAlbumsRequest) (*GetAlbumsResponse, error) {
+	query := url.Values{}
+	query.Set("page", req.Page)
+	query.Set("pageSize", req.PageSize)
+
+	resp, err := c.httpClient.Get(c.BaseURL + "/albums?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newResponseError(resp, "get albums")
+	}
+
+	var response GetAlbumsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (c *SongServiceClient) GetAlbum(req *GetAlbumRequest) (*models.Album, error) {
+	resp, err := c.httpClient.Get(fmt.Sprintf("%s/albums/%s", c.BaseURL, url.PathEscape(req.AlbumId)))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return decodeAlbum(resp, http.StatusOK, "get album")
+}
+
+func (c *SongServiceClient) CreateAlbum(req *CreateAlbumRequest) (*models.Album, error) {
+	body, err := json.Marshal(req.Album)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Post(c.BaseURL+"/albums", "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return decodeAlbum(resp, http.StatusCreated, "create album")
+}
+
+func (c *SongServiceClient) UpdateAlbum(req *UpdateAlbumRequest) (*models.Album, error) {
+	body, err := json.Marshal(req.Album)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequest("PATCH", fmt.Sprintf("%s/albums/%s", c.BaseURL, url.PathEscape(req.AlbumId)), bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return decodeAlbum(resp, http.StatusOK, "update album")
+}
+
+func (c *SongServiceClient) DeleteAlbum(req *DeleteAlbumRequest) error {
+	return c.sendNoContent("DELETE", fmt.Sprintf("%s/albums/%s", c.BaseURL, url.PathEscape(req.AlbumId)), "delete album")
+}
+
+func decodeAlbum(resp *http.Response, status int, action string) (*models.Album, error) {
+	if resp.StatusCode != status {
+		return nil, newResponseError(resp, action)
+	}
+
+	var album models.Album
+	if err := json.NewDecoder(resp.Body).Decode(&album); err != nil {
+		return nil, err
+	}
+	return &album, nil
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"github.com/SZabrodskii/music-library/utils/pagination"
+	"net/http"
+	"net/url"
+)
+
+type GetArtistsRequest struct {
+	Query    string `json:"q"`
+	Page     string `json:"page"`
+	PageSize string `json:"pageSize"`
+}
+
+type GetArtistsResponse struct {
+	Artists []*models.Artist `json:"artists"`
+	pagination.Pagination
+}
+
+type GetArtistRequest struct {
+	ArtistId string `json:"artistId"`
+}
+
+func (c *SongServiceClient) GetArtists(req *GetArtistsRequest) (*GetArtistsResponse, error) {
+	query := url.Values{}
+	query.Set("q", req.Query)
+	query.Set("page", req.Page)
+	query.Set("pageSize", req.PageSize)
+
+	resp, err := c.httpClient.Get(c.BaseURL + "/artists?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newResponseError(resp, "get artists")
+	}
+
+	var response GetArtistsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (c *SongServiceClient) GetArtist(req *GetArtistRequest) (*models.ArtistWithSongs, error) {
+	resp, err := c.httpClient.Get(fmt.Sprintf("%s/artists/%s", c.BaseURL, url.PathEscape(req.ArtistId)))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newResponseError(resp, "get artist")
+	}
+
+	var artist models.ArtistWithSongs
+	if err := json.NewDecoder(resp.Body).Decode(&artist); err != nil {
+		return nil, err
+	}
+	return &artist, nil
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"net/http"
+	"net/url"
+)
+
+type GetGenresResponse struct {
+	Genres []*models.Genre `json:"genres"`
+}
+
+type CreateGenreRequest struct {
+	Genre models.Genre `json:"genre"`
+}
+
+// SongGenreRequest attaches the genre Genre, a slug or name, to the song SongId or detaches it.
+type SongGenreRequest struct {
+	SongId string `json:"songId"`
+	Genre  string `json:"genre"`
+}
+
+func (c *SongServiceClient) GetGenres() (*GetGenresResponse, error) {
+	resp, err := c.httpClient.Get(c.BaseURL + "/genres")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newResponseError(resp, "get genres")
+	}
+
+	var response GetGenresResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (c *SongServiceClient) CreateGenre(req *CreateGenreRequest) (*models.Genre, error) {
+	body, err := json.Marshal(req.Genre)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Post(c.BaseURL+"/genres", "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, newResponseError(resp, "create genre")
+	}
+
+	var genre models.Genre
+	if err := json.NewDecoder(resp.Body).Decode(&genre); err != nil {
+		return nil, err
+	}
+	return &genre, nil
+}
+
+func (c *SongServiceClient) AttachGenre(req *SongGenreRequest) error {
+	return c.sendNoContent("PUT", c.songGenreURL(req), "attach genre")
+}
+
+func (c *SongServiceClient) DetachGenre(req *SongGenreRequest) error {
+	return c.sendNoContent("DELETE", c.songGenreURL(req), "detach genre")
+}
+
+func (c *SongServiceClient) songGenreURL(req *SongGenreRequest) string {
+	return fmt.Sprintf("%s/songs/%s/genres/%s", c.BaseURL, url.PathEscape(req.SongId), url.PathEscape(req.Genre))
+}
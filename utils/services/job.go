@@ -0,0 +1,31 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"net/http"
+	"net/url"
+)
+
+type GetJobRequest struct {
+	JobId string `json:"jobId"`
+}
+
+func (c *SongServiceClient) GetJob(req *GetJobRequest) (*models.Job, error) {
+	resp, err := c.httpClient.Get(fmt.Sprintf("%s/jobs/%s", c.BaseURL, url.PathEscape(req.JobId)))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newResponseError(resp, "get job")
+	}
+
+	var job models.Job
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"github.com/SZabrodskii/music-library/utils/pagination"
+	"net/http"
+	"net/url"
+)
+
+type GetSongHistoryRequest struct {
+	SongId   string `json:"songId"`
+	Page     string `json:"page"`
+	PageSize string `json:"pageSize"`
+}
+
+type GetSongHistoryResponse struct {
+	Revisions []*models.SongRevision `json:"revisions"`
+	pagination.Pagination
+}
+
+type RevertSongRequest struct {
+	SongId   string `json:"songId"`
+	Revision string `json:"revision"`
+	Caller
+}
+
+func (c *SongServiceClient) GetSongHistory(req *GetSongHistoryRequest) (*GetSongHistoryResponse, error) {
+	query := url.Values{}
+	query.Set("page", req.Page)
+	query.Set("pageSize", req.PageSize)
+
+	resp, err := c.httpClient.Get(fmt.Sprintf("%s/songs/%s/history?%s", c.BaseURL, url.PathEscape(req.SongId), query.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newResponseError(resp, "get song history")
+	}
+
+	var response GetSongHistoryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (c *SongServiceClient) RevertSong(req *RevertSongRequest) (*models.Job, error) {
+	target := fmt.Sprintf("%s/songs/%s/revert/%s", c.BaseURL, url.PathEscape(req.SongId), url.PathEscape(req.Revision))
+
+	httpReq, err := http.NewRequest("POST", target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.setHeaders(httpReq)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return decodeJob(resp, "revert song")
+}
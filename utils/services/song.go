@@ -5,18 +5,54 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/SZabrodskii/music-library/utils"
+	"github.com/SZabrodskii/music-library/utils/filters"
+	"github.com/SZabrodskii/music-library/utils/middleware"
 	"github.com/SZabrodskii/music-library/utils/models"
+	"github.com/SZabrodskii/music-library/utils/pagination"
 	"net/http"
+	"net/url"
 )
 
+type ResponseError struct {
+	StatusCode int    `json:"-"`
+	Message    string `json:"error"`
+	// ID is the existing song a 409 Conflict refers to.
+	ID uint `json:"id,omitempty"`
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("%d: %s", e.StatusCode, e.Message)
+}
+
+func newResponseError(resp *http.Response, action string) error {
+	respErr := &ResponseError{StatusCode: resp.StatusCode}
+	if err := json.NewDecoder(resp.Body).Decode(respErr); err != nil || respErr.Message == "" {
+		respErr.Message = fmt.Sprintf("failed to %s: %s", action, resp.Status)
+	}
+	return respErr
+}
+
 type GetSongsRequest struct {
 	Page     string   `json:"page"`
 	PageSize string   `json:"pageSize"`
+	Cursor   string   `json:"cursor"`
+	Limit    string   `json:"limit"`
 	Filters  []string `json:"filters"`
+	Sort     string   `json:"sort"`
+	// ReleasedAfter and ReleasedBefore bound the release date, both inclusive.
+	ReleasedAfter  string `json:"releasedAfter"`
+	ReleasedBefore string `json:"releasedBefore"`
 }
 
 type GetSongsResponse struct {
 	Songs []*models.Song `json:"songs"`
+	// Facets count all songs matching the filters, not only this page, per genre and tag.
+	Facets *models.SongFacets `json:"facets,omitempty"`
+	pagination.Pagination
+}
+
+type GetSongRequest struct {
+	SongId string `json:"songId"`
 }
 
 type GetSongTextRequest struct {
@@ -26,20 +62,67 @@ type GetSongTextRequest struct {
 }
 
 type GetSongTextResponse struct {
-	Verses []models.Verse `json:"verses"`
+	Verses []*models.Verse `json:"verses"`
+	pagination.Pagination
+}
+
+type SearchLyricsRequest struct {
+	Query    string `json:"q"`
+	Page     string `json:"page"`
+	PageSize string `json:"pageSize"`
+}
+
+type SearchLyricsResponse struct {
+	Results []*models.LyricsMatch `json:"results"`
+	pagination.Pagination
+}
+
+type SearchSongsRequest struct {
+	Query    string `json:"q"`
+	Page     string `json:"page"`
+	PageSize string `json:"pageSize"`
+}
+
+type SearchSongsResponse struct {
+	Results []*models.SongMatch `json:"results"`
+	pagination.Pagination
+}
+
+// Caller is who makes a change and the trace it belongs to. It is forwarded in headers so that the song
+// service records it in the song history.
+type Caller struct {
+	Actor       string `json:"-"`
+	Traceparent string `json:"-"`
+}
+
+func (c Caller) setHeaders(req *http.Request) {
+	if c.Actor != "" {
+		req.Header.Set(middleware.ActorHeader, c.Actor)
+	}
+	if c.Traceparent != "" {
+		req.Header.Set("traceparent", c.Traceparent)
+	}
 }
 
 type DeleteSongRequest struct {
 	SongId string `json:"songId"`
+	Caller
 }
 
 type UpdateSongRequest struct {
 	SongID string      `json:"songId"`
 	Song   models.Song `json:"song"`
+	Caller
 }
 
 type AddSongRequest struct {
 	Song models.Song `json:"song"`
+	Caller
+}
+
+type RefreshSongRequest struct {
+	SongID string `json:"songId"`
+	Caller
 }
 
 type SongServiceClientConfig struct {
@@ -66,19 +149,36 @@ func NewSongServiceClient(config *SongServiceClientConfig) *SongServiceClient {
 }
 
 func (c *SongServiceClient) GetSongs(req *GetSongsRequest) (*GetSongsResponse, error) {
-	url := fmt.Sprintf("%s/songs?page=%s&pageSize=%s", c.BaseURL, req.Page, req.PageSize)
-	for _, filter := range req.Filters {
-		url += "&filters=" + filter
+	parsedFilters, err := filters.ParseQuery(req.Filters, req.ReleasedAfter, req.ReleasedBefore)
+	if err != nil {
+		return nil, err
 	}
+	sort, err := filters.ParseSort(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	if req.Cursor != "" || req.Limit != "" {
+		query.Set("cursor", req.Cursor)
+		query.Set("limit", req.Limit)
+	} else {
+		query.Set("page", req.Page)
+		query.Set("pageSize", req.PageSize)
+	}
+	for _, filter := range parsedFilters {
+		query.Add("filters", filter.String())
+	}
+	query.Set("sort", filters.SortString(sort))
 
-	resp, err := c.httpClient.Get(url)
+	resp, err := c.httpClient.Get(c.BaseURL + "/songs?" + query.Encode())
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get songs: %s", resp.Status)
+		return nil, newResponseError(resp, "get songs")
 	}
 
 	var response GetSongsResponse
@@ -88,17 +188,37 @@ func (c *SongServiceClient) GetSongs(req *GetSongsRequest) (*GetSongsResponse, e
 	return &response, nil
 }
 
+func (c *SongServiceClient) GetSong(req *GetSongRequest) (*models.SongWithDetail, error) {
+	resp, err := c.httpClient.Get(fmt.Sprintf("%s/songs/%s", c.BaseURL, url.PathEscape(req.SongId)))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newResponseError(resp, "get song")
+	}
+
+	var response models.SongWithDetail
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
 func (c *SongServiceClient) GetSongText(req *GetSongTextRequest) (*GetSongTextResponse, error) {
-	url := fmt.Sprintf("%s/songs/%s/text?page=%s&pageSize=%s", c.BaseURL, req.SongId, req.Page, req.PageSize)
+	query := url.Values{}
+	query.Set("page", req.Page)
+	query.Set("pageSize", req.PageSize)
 
-	resp, err := c.httpClient.Get(url)
+	resp, err := c.httpClient.Get(fmt.Sprintf("%s/songs/%s/text?%s", c.BaseURL, url.PathEscape(req.SongId), query.Encode()))
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get song text: %s", resp.Status)
+		return nil, newResponseError(resp, "get song text")
 	}
 
 	var response GetSongTextResponse
@@ -108,60 +228,149 @@ func (c *SongServiceClient) GetSongText(req *GetSongTextRequest) (*GetSongTextRe
 	return &response, nil
 }
 
-func (c *SongServiceClient) UpdateSong(req *UpdateSongRequest) error {
-	url := fmt.Sprintf("%s/songs/%s", c.BaseURL, req.SongID)
+func (c *SongServiceClient) SearchLyrics(req *SearchLyricsRequest) (*SearchLyricsResponse, error) {
+	query := url.Values{}
+	query.Set("q", req.Query)
+	query.Set("page", req.Page)
+	query.Set("pageSize", req.PageSize)
+
+	resp, err := c.httpClient.Get(c.BaseURL + "/search?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newResponseError(resp, "search lyrics")
+	}
+
+	var response SearchLyricsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (c *SongServiceClient) SearchSongs(req *SearchSongsRequest) (*SearchSongsResponse, error) {
+	query := url.Values{}
+	query.Set("q", req.Query)
+	query.Set("page", req.Page)
+	query.Set("pageSize", req.PageSize)
+
+	resp, err := c.httpClient.Get(c.BaseURL + "/search/songs?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newResponseError(resp, "search songs")
+	}
+
+	var response SearchSongsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (c *SongServiceClient) UpdateSong(req *UpdateSongRequest) (*models.Job, error) {
+	target := fmt.Sprintf("%s/songs/%s", c.BaseURL, url.PathEscape(req.SongID))
 	body, err := json.Marshal(req.Song)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	httpReq, err := http.NewRequest("PATCH", url, bytes.NewBuffer(body))
+	httpReq, err := http.NewRequest("PATCH", target, bytes.NewBuffer(body))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
+	req.setHeaders(httpReq)
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to update song: %s", resp.Status)
-	}
-	return nil
+	return decodeJob(resp, "update song")
 }
 
-func (c *SongServiceClient) AddSong(req *AddSongRequest) error {
+func (c *SongServiceClient) AddSong(req *AddSongRequest) (*models.Job, error) {
 	url := fmt.Sprintf("%s/songs", c.BaseURL)
 	body, err := json.Marshal(req.Song)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	httpReq, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
+	req.setHeaders(httpReq)
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("failed to add song: %s", resp.Status)
+	return decodeJob(resp, "add song")
+}
+
+func (c *SongServiceClient) DeleteSong(req *DeleteSongRequest) (*models.Job, error) {
+	target := fmt.Sprintf("%s/songs/%s", c.BaseURL, url.PathEscape(req.SongId))
+
+	httpReq, err := http.NewRequest("DELETE", target, nil)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	req.setHeaders(httpReq)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return decodeJob(resp, "delete song")
+}
+
+func (c *SongServiceClient) RefreshSong(req *RefreshSongRequest) (*models.Job, error) {
+	target := fmt.Sprintf("%s/songs/%s/refresh", c.BaseURL, url.PathEscape(req.SongID))
+
+	httpReq, err := http.NewRequest("POST", target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.setHeaders(httpReq)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return decodeJob(resp, "refresh song")
 }
 
-func (c *SongServiceClient) DeleteSong(req *DeleteSongRequest) error {
-	url := fmt.Sprintf("%s/songs/%s", c.BaseURL, req.SongId)
+func decodeJob(resp *http.Response, action string) (*models.Job, error) {
+	if resp.StatusCode != http.StatusAccepted {
+		return nil, newResponseError(resp, action)
+	}
+
+	var job models.Job
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
 
-	httpReq, err := http.NewRequest("DELETE", url, nil)
+// sendNoContent sends a request without a body that is expected to answer 204 No Content.
+func (c *SongServiceClient) sendNoContent(method, target, action string) error {
+	httpReq, err := http.NewRequest(method, target, nil)
 	if err != nil {
 		return err
 	}
@@ -173,7 +382,7 @@ func (c *SongServiceClient) DeleteSong(req *DeleteSongRequest) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("failed to delete song: %s", resp.Status)
+		return newResponseError(resp, action)
 	}
 	return nil
 }
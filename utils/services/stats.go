@@ -0,0 +1,45 @@
+package services
+
+import (
+	"encoding/json"
+	"github.com/SZabrodskii/music-library/utils/filters"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"net/http"
+	"net/url"
+)
+
+type GetFacetStatsRequest struct {
+	By             string   `json:"by"`
+	Filters        []string `json:"filters"`
+	ReleasedAfter  string   `json:"releasedAfter"`
+	ReleasedBefore string   `json:"releasedBefore"`
+}
+
+func (c *SongServiceClient) GetFacetStats(req *GetFacetStatsRequest) (*models.FacetStats, error) {
+	parsedFilters, err := filters.ParseQuery(req.Filters, req.ReleasedAfter, req.ReleasedBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("by", req.By)
+	for _, filter := range parsedFilters {
+		query.Add("filters", filter.String())
+	}
+
+	resp, err := c.httpClient.Get(c.BaseURL + "/stats/facets?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newResponseError(resp, "get facet stats")
+	}
+
+	var stats models.FacetStats
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/SZabrodskii/music-library/utils/models"
+	"github.com/SZabrodskii/music-library/utils/pagination"
+	"net/http"
+	"net/url"
+)
+
+type GetTagsRequest struct {
+	Query    string `json:"q"`
+	Page     string `json:"page"`
+	PageSize string `json:"pageSize"`
+}
+
+type GetTagsResponse struct {
+	Tags []*models.Tag `json:"tags"`
+	pagination.Pagination
+}
+
+type SongTagRequest struct {
+	SongId string `json:"songId"`
+	Tag    string `json:"tag"`
+}
+
+func (c *SongServiceClient) GetTags(req *GetTagsRequest) (*GetTagsResponse, error) {
+	query := url.Values{}
+	query.Set("q", req.Query)
+	query.Set("page", req.Page)
+	query.Set("pageSize", req.PageSize)
+
+	resp, err := c.httpClient.Get(c.BaseURL + "/tags?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newResponseError(resp, "get tags")
+	}
+
+	var response GetTagsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (c *SongServiceClient) AttachTag(req *SongTagRequest) error {
+	return c.sendNoContent("PUT", c.songTagURL(req), "attach tag")
+}
+
+func (c *SongServiceClient) DetachTag(req *SongTagRequest) error {
+	return c.sendNoContent("DELETE", c.songTagURL(req), "detach tag")
+}
+
+func (c *SongServiceClient) songTagURL(req *SongTagRequest) string {
+	return fmt.Sprintf("%s/songs/%s/tags/%s", c.BaseURL, url.PathEscape(req.SongId), url.PathEscape(req.Tag))
+}